@@ -99,6 +99,14 @@ func (cli *CLI) Run(args []string) int {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Max workers should be greater than 0"), map[string][]byte{})
 		return ExitCodeError
 	}
+	if opts.SandboxDir != "" {
+		// Relative paths are resolved from the original working directory, regardless of --chdir.
+		opts.SandboxDir, err = filepath.Abs(opts.SandboxDir)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to resolve the sandbox directory; %w", err), map[string][]byte{})
+			return ExitCodeError
+		}
+	}
 
 	switch {
 	case opts.Version:
@@ -7,6 +7,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/afero"
 	"github.com/nholuongut/tflint/plugin"
+	"github.com/nholuongut/tflint/terraform"
 	"github.com/nholuongut/tflint/tflint"
 )
 
@@ -32,6 +33,7 @@ func (cli *CLI) init(opts Options) int {
 			if err != nil {
 				return fmt.Errorf("Failed to load TFLint config; %w", err)
 			}
+			cfg.Merge(opts.toConfig())
 
 			found := false
 			for _, pluginCfg := range cfg.Plugins {
@@ -72,6 +74,11 @@ func (cli *CLI) init(opts Options) int {
 				fmt.Fprint(cli.outStream, "No plugins to install\n")
 			}
 
+			// Remote modules are only called with call_module_type = "all".
+			if cfg.CallModuleType == terraform.CallAllModule {
+				return cli.installModules(cfg)
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -82,3 +89,31 @@ func (cli *CLI) init(opts Options) int {
 
 	return ExitCodeOK
 }
+
+// installModules installs remote modules into the module cache, so that
+// they can be inspected without "terraform init".
+func (cli *CLI) installModules(cfg *tflint.Config) error {
+	loader, err := terraform.NewLoader(afero.Afero{Fs: afero.NewOsFs()}, cli.originalWorkingDir)
+	if err != nil {
+		return fmt.Errorf("Failed to prepare loading; %w", err)
+	}
+	loader.SetLanguage(cfg.Language)
+
+	installed, diags := loader.InstallModules(".")
+	for _, mod := range installed {
+		if mod.Cached {
+			fmt.Fprintf(cli.outStream, "Module \"%s\" is already installed\n", mod.Key)
+			continue
+		}
+		if mod.Version != nil {
+			fmt.Fprintf(cli.outStream, "Installed \"%s\" module (source: %s, version: %s)\n", mod.Key, mod.Source, mod.Version)
+		} else {
+			fmt.Fprintf(cli.outStream, "Installed \"%s\" module (source: %s)\n", mod.Key, mod.Source)
+		}
+	}
+	if diags.HasErrors() {
+		return fmt.Errorf("Failed to install modules; %w", diags)
+	}
+
+	return nil
+}
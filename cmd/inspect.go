@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/go-version"
@@ -75,7 +76,9 @@ func (cli *CLI) inspect(opts Options) int {
 		}
 	}
 
-	if len(issues) > 0 && !cli.config.Force && exceedsMinimumFailure(issues, opts.MinimumFailureSeverity) {
+	// Suppressed issues are output only in JSON format and do not affect the exit status
+	unsuppressed := issues.Unsuppressed()
+	if len(unsuppressed) > 0 && !cli.config.Force && exceedsMinimumFailure(unsuppressed, opts.MinimumFailureSeverity) {
 		return ExitCodeIssuesFound
 	}
 
@@ -99,15 +102,56 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 	if err != nil {
 		return issues, changes, fmt.Errorf("Failed to prepare loading; %w", err)
 	}
+	cli.loader.SetLanguage(cli.config.Language)
 	if opts.ActAsWorker && !cli.loader.IsConfigDir(dir) {
 		// Ignore non-module directories in worker mode
 		return issues, changes, nil
 	}
 
 	// Setup runners
-	rootRunner, moduleRunners, err := cli.setupRunners(opts, dir)
-	if err != nil {
-		return issues, changes, err
+	//
+	// If multiple workspaces or scenarios are given, runners are set up for each
+	// combination so that the module is inspected against each of them.
+	workspaces := []string{""}
+	if len(cli.config.Workspaces) > 1 {
+		workspaces = cli.config.Workspaces
+	}
+	scenarios := []*tflint.ScenarioConfig{nil}
+	if len(cli.config.Scenarios) > 0 {
+		scenarios = cli.config.Scenarios
+	}
+	if opts.Fix && len(workspaces)*len(scenarios) > 1 {
+		return issues, changes, fmt.Errorf("Autofix is not supported when inspecting multiple workspaces or scenarios")
+	}
+
+	runnerSets := []*runnerSet{}
+	for _, workspace := range workspaces {
+		for _, scenario := range scenarios {
+			set := &runnerSet{workspace: workspace}
+			config := cli.config
+			if workspace != "" {
+				config = config.ForWorkspace(workspace)
+			}
+			if scenario != nil {
+				set.scenario = scenario.Name
+				config = config.ForScenario(scenario)
+			}
+
+			var warnings hcl.Diagnostics
+			set.root, set.modules, warnings, err = cli.setupRunners(opts, config, dir)
+			if err != nil {
+				if name := set.String(); name != "" {
+					return issues, changes, fmt.Errorf("Failed to set up %s; %w", name, err)
+				}
+				return issues, changes, err
+			}
+			// Modules are loaded in the same way regardless of workspaces and scenarios,
+			// so the warnings are output only once.
+			if len(runnerSets) == 0 {
+				cli.formatter.PrintWarnings(warnings, cli.loader.Sources())
+			}
+			runnerSets = append(runnerSets, set)
+		}
 	}
 
 	// Launch plugin processes
@@ -141,6 +185,59 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 		sdkVersions[name] = sdkVersion
 	}
 
+	for _, set := range runnerSets {
+		found, fixed, err := cli.inspectRunners(opts, rulesetPlugin, sdkVersions, set.root, set.modules, filterFiles)
+		if err != nil {
+			return issues, changes, err
+		}
+		issues = mergeIssues(issues, found, set)
+		for path, source := range fixed {
+			changes[path] = source
+		}
+	}
+
+	if cli.config.ReportUnusedAnnotations {
+		unused, err := unusedAnnotationIssues(rulesetPlugin, runnerSets, filterFiles)
+		if err != nil {
+			return issues, changes, err
+		}
+		issues = append(issues, unused...)
+	}
+
+	untagCommonIssues(issues, len(cli.config.Workspaces), len(cli.config.Scenarios))
+
+	// Set module sources to CLI
+	for path, source := range cli.loader.Sources() {
+		cli.sources[path] = source
+	}
+
+	return issues, changes, nil
+}
+
+// runnerSet is a set of runners for a root module and its module calls.
+// The workspace and scenario are set only when inspecting multiple workspaces or scenarios.
+type runnerSet struct {
+	workspace string
+	scenario  string
+	root      *tflint.Runner
+	modules   []*tflint.Runner
+}
+
+func (s *runnerSet) String() string {
+	names := []string{}
+	if s.workspace != "" {
+		names = append(names, fmt.Sprintf(`workspace "%s"`, s.workspace))
+	}
+	if s.scenario != "" {
+		names = append(names, fmt.Sprintf(`scenario "%s"`, s.scenario))
+	}
+	return strings.Join(names, ", ")
+}
+
+func (cli *CLI) inspectRunners(opts Options, rulesetPlugin *plugin.Plugin, sdkVersions map[string]*version.Version, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner, filterFiles []string) (tflint.Issues, map[string][]byte, error) {
+	issues := tflint.Issues{}
+	changes := map[string][]byte{}
+
 	// Run inspection
 	//
 	// Repeat an inspection until there are no more changes or the limit is reached,
@@ -161,6 +258,7 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 			}
 			// Run checks for module calls are performed in parallel.
 			// The rootRunner is shared between goroutines but read-only, so this is goroutine-safe.
+			// The only exception is issues emitted while evaluating expressions, which are guarded by the runner.
 			// Note that checks against the rootRunner are not parallelized, as autofix may cause the module to be rebuilt.
 			ch := make(chan error, len(moduleRunners))
 			for _, runner := range moduleRunners {
@@ -173,8 +271,7 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 				}
 			}
 			for i := 0; i < len(moduleRunners); i++ {
-				err = <-ch
-				if err != nil {
+				if err := <-ch; err != nil {
 					return issues, changes, fmt.Errorf("Failed to check ruleset; %w", err)
 				}
 			}
@@ -203,58 +300,136 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 		}
 	}
 
-	// Set module sources to CLI
-	for path, source := range cli.loader.Sources() {
-		cli.sources[path] = source
+	return issues, changes, nil
+}
+
+// mergeIssues merges the issues found by the runner set into the issues found so far.
+// Identical issues found in multiple runner sets are reported once, tagged with
+// all the workspaces and scenarios where they were found.
+func mergeIssues(issues tflint.Issues, found tflint.Issues, set *runnerSet) tflint.Issues {
+	// Issues are only merged with those found by other runner sets
+	known := len(issues)
+
+	for _, issue := range found {
+		idx := slices.IndexFunc(issues[:known], func(existing *tflint.Issue) bool {
+			return existing.Rule.Name() == issue.Rule.Name() &&
+				existing.Message == issue.Message &&
+				existing.Range == issue.Range &&
+				slices.Equal(existing.Callers, issue.Callers)
+		})
+		if idx >= 0 {
+			issue = issues[idx]
+		} else {
+			issues = append(issues, issue)
+		}
+
+		if set.workspace != "" && !slices.Contains(issue.Workspaces, set.workspace) {
+			issue.Workspaces = append(issue.Workspaces, set.workspace)
+		}
+		if set.scenario != "" && !slices.Contains(issue.Scenarios, set.scenario) {
+			issue.Scenarios = append(issue.Scenarios, set.scenario)
+		}
 	}
+	return issues
+}
 
-	return issues, changes, nil
+// unusedAnnotationIssues returns issues for annotations that did not ignore any issues
+// or refer to rules not provided by the loaded plugins. Annotations used in any of
+// the runner sets are not reported, so only issues found in all runner sets are returned.
+func unusedAnnotationIssues(rulesetPlugin *plugin.Plugin, sets []*runnerSet, filterFiles []string) (tflint.Issues, error) {
+	ruleNames := []string{}
+	for name, ruleset := range rulesetPlugin.RuleSets {
+		names, err := ruleset.RuleNames()
+		if err != nil {
+			return nil, fmt.Errorf(`Failed to get rule names from "%s" plugin; %w`, name, err)
+		}
+		ruleNames = append(ruleNames, names...)
+	}
+
+	issues := tflint.Issues{}
+	for i, set := range sets {
+		found := set.root.UnusedAnnotationIssues(ruleNames)
+		if i == 0 {
+			issues = found
+			continue
+		}
+		issues = slices.DeleteFunc(issues, func(issue *tflint.Issue) bool {
+			return !slices.ContainsFunc(found, func(other *tflint.Issue) bool {
+				return other.Message == issue.Message && other.Range == issue.Range
+			})
+		})
+	}
+
+	if len(filterFiles) == 0 {
+		return issues, nil
+	}
+	return slices.DeleteFunc(issues, func(issue *tflint.Issue) bool {
+		return !slices.ContainsFunc(filterFiles, func(file string) bool {
+			return filepath.Clean(file) == filepath.Clean(issue.Range.Filename)
+		})
+	}), nil
 }
 
-func (cli *CLI) setupRunners(opts Options, dir string) (*tflint.Runner, []*tflint.Runner, error) {
-	configs, diags := cli.loader.LoadConfig(dir, cli.config.CallModuleType)
+// untagCommonIssues removes the tags from issues found in all workspaces or scenarios,
+// so that only the issues that differ between them are labeled.
+func untagCommonIssues(issues tflint.Issues, workspaces int, scenarios int) {
+	for _, issue := range issues {
+		if len(issue.Workspaces) == workspaces {
+			issue.Workspaces = nil
+		}
+		if len(issue.Scenarios) == scenarios {
+			issue.Scenarios = nil
+		}
+	}
+}
+
+func (cli *CLI) setupRunners(opts Options, config *tflint.Config, dir string) (*tflint.Runner, []*tflint.Runner, hcl.Diagnostics, error) {
+	configs, diags := cli.loader.LoadConfig(dir, config.CallModuleType)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to load configurations; %w", diags)
 	}
+	// Diagnostics without errors are warnings, such as modules that need to be reinstalled
+	warnings := diags
 
 	files, diags := cli.loader.LoadConfigDirFiles(dir)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to load configurations; %w", diags)
 	}
 	annotations := map[string]tflint.Annotations{}
 	for path, file := range files {
-		if !strings.HasSuffix(path, ".tf") {
-			continue
-		}
 		ants, lexDiags := tflint.NewAnnotations(path, file)
 		diags = diags.Extend(lexDiags)
 		annotations[path] = ants
 	}
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to load configurations; %w", diags)
 	}
 
-	variables, diags := cli.loader.LoadValuesFiles(dir, cli.config.Varfiles...)
+	variables, diags := cli.loader.LoadValuesFiles(dir, config.Varfiles...)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load values files; %w", diags)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to load values files; %w", diags)
 	}
-	cliVars, diags := terraform.ParseVariableValues(cli.config.Variables, configs.Module.Variables)
+	cliVars, diags := terraform.ParseVariableValues(config.Variables, configs.Module.Variables)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to parse variables; %w", diags)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to parse variables; %w", diags)
 	}
 	variables = append(variables, cliVars)
 
-	runner, err := tflint.NewRunner(cli.originalWorkingDir, cli.config, annotations, configs, variables...)
+	runner, err := tflint.NewRunner(cli.originalWorkingDir, config, annotations, configs, variables...)
 	if err != nil {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to initialize a runner; %w", err)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to initialize a runner; %w", err)
 	}
 
 	moduleRunners, err := tflint.NewModuleRunners(runner)
 	if err != nil {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to prepare rule checking; %w", err)
+		return nil, []*tflint.Runner{}, nil, fmt.Errorf("Failed to prepare rule checking; %w", err)
+	}
+
+	for _, r := range append(moduleRunners, runner) {
+		r.CheckConditions()
 	}
 
-	return runner, moduleRunners, nil
+	return runner, moduleRunners, warnings, nil
 }
 
 func launchPlugins(config *tflint.Config, fix bool) (*plugin.Plugin, error) {
@@ -94,7 +94,8 @@ func (cli *CLI) inspectParallel(opts Options) int {
 		return ExitCodeError
 	}
 
-	if len(issues) > 0 && !force && exceedsMinimumFailure(issues, opts.MinimumFailureSeverity) {
+	unsuppressed := issues.Unsuppressed()
+	if len(unsuppressed) > 0 && !force && exceedsMinimumFailure(unsuppressed, opts.MinimumFailureSeverity) {
 		return ExitCodeIssuesFound
 	}
 
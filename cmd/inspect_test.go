@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	hcl "github.com/hashicorp/hcl/v2"
+	sdk "github.com/nholuongut/tflint-plugin-sdk/tflint"
+	"github.com/nholuongut/tflint/tflint"
+)
+
+type testRule struct {
+	name string
+}
+
+func (r *testRule) Name() string              { return r.name }
+func (r *testRule) Severity() tflint.Severity { return sdk.ERROR }
+func (r *testRule) Link() string              { return "" }
+
+func Test_mergeIssues(t *testing.T) {
+	rule1 := &testRule{name: "rule1"}
+	rule2 := &testRule{name: "rule2"}
+	range1 := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1, Column: 1}, End: hcl.Pos{Line: 1, Column: 5}}
+	range2 := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 2, Column: 1}, End: hcl.Pos{Line: 2, Column: 5}}
+	caller1 := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 3, Column: 1}, End: hcl.Pos{Line: 3, Column: 5}}
+	caller2 := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 4, Column: 1}, End: hcl.Pos{Line: 4, Column: 5}}
+
+	tests := []struct {
+		name       string
+		sets       []*runnerSet
+		found      []tflint.Issues
+		workspaces int
+		scenarios  int
+		want       tflint.Issues
+	}{
+		{
+			name: "single runner set",
+			sets: []*runnerSet{{}},
+			found: []tflint.Issues{
+				{
+					{Rule: rule1, Message: "foo", Range: range1, Callers: []hcl.Range{caller1}},
+					{Rule: rule1, Message: "foo", Range: range1, Callers: []hcl.Range{caller2}},
+				},
+			},
+			want: tflint.Issues{
+				{Rule: rule1, Message: "foo", Range: range1, Callers: []hcl.Range{caller1}},
+				{Rule: rule1, Message: "foo", Range: range1, Callers: []hcl.Range{caller2}},
+			},
+		},
+		{
+			name: "multiple workspaces",
+			sets: []*runnerSet{{workspace: "dev"}, {workspace: "prod"}},
+			found: []tflint.Issues{
+				{
+					{Rule: rule1, Message: "foo", Range: range1},
+					{Rule: rule2, Message: "bar", Range: range1},
+				},
+				{
+					{Rule: rule1, Message: "foo", Range: range1},
+					{Rule: rule1, Message: "foo", Range: range2},
+					{Rule: rule2, Message: "baz", Range: range1},
+				},
+			},
+			workspaces: 2,
+			want: tflint.Issues{
+				{Rule: rule1, Message: "foo", Range: range1},
+				{Rule: rule2, Message: "bar", Range: range1, Workspaces: []string{"dev"}},
+				{Rule: rule1, Message: "foo", Range: range2, Workspaces: []string{"prod"}},
+				{Rule: rule2, Message: "baz", Range: range1, Workspaces: []string{"prod"}},
+			},
+		},
+		{
+			name: "multiple scenarios",
+			sets: []*runnerSet{{scenario: "dev"}, {scenario: "stg"}, {scenario: "prod"}},
+			found: []tflint.Issues{
+				{
+					{Rule: rule1, Message: "foo", Range: range1},
+					{Rule: rule1, Message: "foo", Range: range2},
+				},
+				{
+					{Rule: rule1, Message: "foo", Range: range1},
+					{Rule: rule1, Message: "foo", Range: range2},
+				},
+				{
+					{Rule: rule1, Message: "foo", Range: range1},
+				},
+			},
+			scenarios: 3,
+			want: tflint.Issues{
+				{Rule: rule1, Message: "foo", Range: range1},
+				{Rule: rule1, Message: "foo", Range: range2, Scenarios: []string{"dev", "stg"}},
+			},
+		},
+		{
+			name: "multiple workspaces and scenarios",
+			sets: []*runnerSet{
+				{workspace: "dev", scenario: "small"},
+				{workspace: "dev", scenario: "large"},
+				{workspace: "prod", scenario: "small"},
+				{workspace: "prod", scenario: "large"},
+			},
+			found: []tflint.Issues{
+				{{Rule: rule1, Message: "foo", Range: range1}},
+				{{Rule: rule1, Message: "foo", Range: range1}},
+				{},
+				{{Rule: rule1, Message: "foo", Range: range2}},
+			},
+			workspaces: 2,
+			scenarios:  2,
+			want: tflint.Issues{
+				{Rule: rule1, Message: "foo", Range: range1, Workspaces: []string{"dev"}},
+				{Rule: rule1, Message: "foo", Range: range2, Workspaces: []string{"prod"}, Scenarios: []string{"large"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues := tflint.Issues{}
+			for i, set := range test.sets {
+				issues = mergeIssues(issues, test.found[i], set)
+			}
+			untagCommonIssues(issues, test.workspaces, test.scenarios)
+
+			if diff := cmp.Diff(test.want, issues, cmpopts.IgnoreUnexported(testRule{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCLIRun_moduleManifestWarnings(t *testing.T) {
+	// Disable the bundled plugin because the `os.Executable()` is go(1) in the tests
+	tflint.DisableBundledPlugin = true
+	defer func() {
+		tflint.DisableBundledPlugin = false
+	}()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		want   []string
+	}{
+		{
+			name: "no changes",
+			args: []string{"./tflint", "--call-module-type=all", "--format=json", "--no-color"},
+			config: `
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "0.9.0"
+}`,
+			want: []string{},
+		},
+		{
+			name: "source changed",
+			args: []string{"./tflint", "--call-module-type=all", "--format=json", "--no-color"},
+			config: `
+module "consul" {
+  source  = "hashicorp/vault/aws"
+  version = "0.9.0"
+}`,
+			want: []string{"Module source has changed"},
+		},
+		{
+			name: "version changed",
+			args: []string{"./tflint", "--call-module-type=all", "--format=json", "--no-color"},
+			config: `
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = ">= 1.0"
+}`,
+			want: []string{"Module version requirements have changed"},
+		},
+		{
+			name: "multiple workspaces",
+			args: []string{"./tflint", "--call-module-type=all", "--format=json", "--no-color", "--workspace=dev", "--workspace=prod"},
+			config: `
+module "consul" {
+  source  = "hashicorp/vault/aws"
+  version = "0.9.0"
+}`,
+			want: []string{"Module source has changed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("TFLINT_MODULE_CACHE_DIR", t.TempDir())
+
+			dir := t.TempDir()
+			files := map[string]string{
+				"main.tf": test.config,
+				".terraform/modules/modules.json": `{"Modules":[
+  {"Key": "", "Source": "", "Dir": "."},
+  {"Key": "consul", "Source": "registry.terraform.io/hashicorp/consul/aws", "Version": "0.9.0", "Dir": ".terraform/modules/consul"}
+]}`,
+				".terraform/modules/consul/main.tf": ``,
+			}
+			for name, src := range files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			currentDir, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := os.Chdir(currentDir); err != nil {
+					t.Fatal(err)
+				}
+			}()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+			cli, err := NewCLI(outStream, errStream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status := cli.Run(test.args); status != ExitCodeOK {
+				t.Fatalf("expected status is %d, but got %d: %s", ExitCodeOK, status, errStream.String())
+			}
+
+			// Warnings do not break the output of machine-readable formats
+			if got := strings.TrimSpace(outStream.String()); got != `{"issues":[],"errors":[]}` {
+				t.Errorf("unexpected stdout: %s", got)
+			}
+
+			got := []string{}
+			for _, line := range strings.Split(errStream.String(), "\n") {
+				if summary, ok := strings.CutPrefix(line, "Warning: "); ok {
+					got = append(got, summary)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -11,31 +11,35 @@ import (
 
 // Options is an option specified by arguments.
 type Options struct {
-	Version                bool     `short:"v" long:"version" description:"Print TFLint version"`
-	Init                   bool     `long:"init" description:"Install plugins"`
-	Langserver             bool     `long:"langserver" description:"Start language server"`
-	Format                 string   `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif"`
-	Config                 string   `short:"c" long:"config" description:"Config file name (default: .tflint.hcl)" value-name:"FILE"`
-	IgnoreModules          []string `long:"ignore-module" description:"Ignore module sources" value-name:"SOURCE"`
-	EnableRules            []string `long:"enable-rule" description:"Enable rules from the command line" value-name:"RULE_NAME"`
-	DisableRules           []string `long:"disable-rule" description:"Disable rules from the command line" value-name:"RULE_NAME"`
-	Only                   []string `long:"only" description:"Enable only this rule, disabling all other defaults. Can be specified multiple times" value-name:"RULE_NAME"`
-	EnablePlugins          []string `long:"enable-plugin" description:"Enable plugins from the command line" value-name:"PLUGIN_NAME"`
-	Varfiles               []string `long:"var-file" description:"Terraform variable file name" value-name:"FILE"`
-	Variables              []string `long:"var" description:"Set a Terraform variable" value-name:"'foo=bar'"`
-	CallModuleType         *string  `long:"call-module-type" description:"Types of module to call (default: local)" choice:"all" choice:"local" choice:"none"`
-	Chdir                  string   `long:"chdir" description:"Switch to a different working directory before executing the command" value-name:"DIR"`
-	Recursive              bool     `long:"recursive" description:"Run command in each directory recursively"`
-	Filter                 []string `long:"filter" description:"Filter issues by file names or globs" value-name:"FILE"`
-	Force                  *bool    `long:"force" description:"Return zero exit status even if issues found"`
-	MinimumFailureSeverity string   `long:"minimum-failure-severity" description:"Sets minimum severity level for exiting with a non-zero error code" choice:"error" choice:"warning" choice:"notice"`
-	Color                  bool     `long:"color" description:"Enable colorized output"`
-	NoColor                bool     `long:"no-color" description:"Disable colorized output"`
-	Fix                    bool     `long:"fix" description:"Fix issues automatically"`
-	NoParallelRunners      bool     `long:"no-parallel-runners" description:"Disable per-runner parallelism"`
-	MaxWorkers             *int     `long:"max-workers" description:"Set maximum number of workers in recursive inspection (default: number of CPUs)" value-name:"N"`
-	ActAsBundledPlugin     bool     `long:"act-as-bundled-plugin" hidden:"true"`
-	ActAsWorker            bool     `long:"act-as-worker" hidden:"true"`
+	Version                 bool     `short:"v" long:"version" description:"Print TFLint version"`
+	Init                    bool     `long:"init" description:"Install plugins and remote modules"`
+	Langserver              bool     `long:"langserver" description:"Start language server"`
+	Format                  string   `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif"`
+	Config                  string   `short:"c" long:"config" description:"Config file name (default: .tflint.hcl)" value-name:"FILE"`
+	IgnoreModules           []string `long:"ignore-module" description:"Ignore module sources" value-name:"SOURCE"`
+	EnableRules             []string `long:"enable-rule" description:"Enable rules from the command line" value-name:"RULE_NAME"`
+	DisableRules            []string `long:"disable-rule" description:"Disable rules from the command line" value-name:"RULE_NAME"`
+	Only                    []string `long:"only" description:"Enable only this rule, disabling all other defaults. Can be specified multiple times" value-name:"RULE_NAME"`
+	EnablePlugins           []string `long:"enable-plugin" description:"Enable plugins from the command line" value-name:"PLUGIN_NAME"`
+	Varfiles                []string `long:"var-file" description:"Terraform variable file name" value-name:"FILE"`
+	Variables               []string `long:"var" description:"Set a Terraform variable" value-name:"'foo=bar'"`
+	Workspaces              []string `long:"workspace" description:"Set terraform.workspace. Can be specified multiple times to inspect each workspace" value-name:"NAME"`
+	SandboxDir              string   `long:"sandbox-dir" description:"Restrict filesystem functions to files in the directory" value-name:"DIR"`
+	Deterministic           bool     `long:"deterministic" description:"Return fixed values from impure functions such as timestamp and uuid"`
+	ReportUnusedAnnotations bool     `long:"report-unused-annotations" description:"Report annotations that did not ignore any issues"`
+	CallModuleType          *string  `long:"call-module-type" description:"Types of module to call (default: local)" choice:"all" choice:"local" choice:"none"`
+	Chdir                   string   `long:"chdir" description:"Switch to a different working directory before executing the command" value-name:"DIR"`
+	Recursive               bool     `long:"recursive" description:"Run command in each directory recursively"`
+	Filter                  []string `long:"filter" description:"Filter issues by file names or globs" value-name:"FILE"`
+	Force                   *bool    `long:"force" description:"Return zero exit status even if issues found"`
+	MinimumFailureSeverity  string   `long:"minimum-failure-severity" description:"Sets minimum severity level for exiting with a non-zero error code" choice:"error" choice:"warning" choice:"notice"`
+	Color                   bool     `long:"color" description:"Enable colorized output"`
+	NoColor                 bool     `long:"no-color" description:"Disable colorized output"`
+	Fix                     bool     `long:"fix" description:"Fix issues automatically"`
+	NoParallelRunners       bool     `long:"no-parallel-runners" description:"Disable per-runner parallelism"`
+	MaxWorkers              *int     `long:"max-workers" description:"Set maximum number of workers in recursive inspection (default: number of CPUs)" value-name:"N"`
+	ActAsBundledPlugin      bool     `long:"act-as-bundled-plugin" hidden:"true"`
+	ActAsWorker             bool     `long:"act-as-worker" hidden:"true"`
 }
 
 func (opts *Options) toConfig() *tflint.Config {
@@ -80,6 +84,10 @@ func (opts *Options) toConfig() *tflint.Config {
 	log.Printf("[DEBUG]   Format: %s", opts.Format)
 	log.Printf("[DEBUG]   Varfiles: %s", strings.Join(opts.Varfiles, ", "))
 	log.Printf("[DEBUG]   Variables: %s", strings.Join(opts.Variables, ", "))
+	log.Printf("[DEBUG]   Workspaces: %s", strings.Join(opts.Workspaces, ", "))
+	log.Printf("[DEBUG]   SandboxDir: %s", opts.SandboxDir)
+	log.Printf("[DEBUG]   Deterministic: %t", opts.Deterministic)
+	log.Printf("[DEBUG]   ReportUnusedAnnotations: %t", opts.ReportUnusedAnnotations)
 	log.Printf("[DEBUG]   EnableRules: %s", strings.Join(opts.EnableRules, ", "))
 	log.Printf("[DEBUG]   DisableRules: %s", strings.Join(opts.DisableRules, ", "))
 	log.Printf("[DEBUG]   Only: %s", strings.Join(opts.Only, ", "))
@@ -131,8 +139,16 @@ func (opts *Options) toConfig() *tflint.Config {
 		DisabledByDefault:    len(opts.Only) > 0,
 		DisabledByDefaultSet: len(opts.Only) > 0,
 
+		Deterministic:    opts.Deterministic,
+		DeterministicSet: opts.Deterministic,
+
+		ReportUnusedAnnotations:    opts.ReportUnusedAnnotations,
+		ReportUnusedAnnotationsSet: opts.ReportUnusedAnnotations,
+
 		Varfiles:      varfiles,
 		Variables:     opts.Variables,
+		Workspaces:    opts.Workspaces,
+		SandboxDir:    opts.SandboxDir,
 		Only:          opts.Only,
 		IgnoreModules: ignoreModules,
 		Rules:         rules,
@@ -178,6 +194,18 @@ func (opts *Options) toWorkerCommands(workingDir string) []string {
 	for _, variable := range opts.Variables {
 		commands = append(commands, fmt.Sprintf("--var=%s", variable))
 	}
+	for _, workspace := range opts.Workspaces {
+		commands = append(commands, fmt.Sprintf("--workspace=%s", workspace))
+	}
+	if opts.SandboxDir != "" {
+		commands = append(commands, fmt.Sprintf("--sandbox-dir=%s", opts.SandboxDir))
+	}
+	if opts.Deterministic {
+		commands = append(commands, "--deterministic")
+	}
+	if opts.ReportUnusedAnnotations {
+		commands = append(commands, "--report-unused-annotations")
+	}
 	if opts.CallModuleType != nil {
 		commands = append(commands, fmt.Sprintf("--call-module-type=%s", *opts.CallModuleType))
 	}
@@ -37,6 +37,68 @@ func Test_toConfig(t *testing.T) {
 				Plugins:           map[string]*tflint.PluginConfig{},
 			},
 		},
+		{
+			Name:    "--workspace",
+			Command: "./tflint --workspace dev --workspace prod",
+			Expected: &tflint.Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				Workspaces:        []string{"dev", "prod"},
+				DisabledByDefault: false,
+				Rules:             map[string]*tflint.RuleConfig{},
+				Plugins:           map[string]*tflint.PluginConfig{},
+			},
+		},
+		{
+			Name:    "--sandbox-dir",
+			Command: "./tflint --sandbox-dir /sandbox",
+			Expected: &tflint.Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				SandboxDir:        "/sandbox",
+				DisabledByDefault: false,
+				Rules:             map[string]*tflint.RuleConfig{},
+				Plugins:           map[string]*tflint.PluginConfig{},
+			},
+		},
+		{
+			Name:    "--deterministic",
+			Command: "./tflint --deterministic",
+			Expected: &tflint.Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				Deterministic:     true,
+				DeterministicSet:  true,
+				DisabledByDefault: false,
+				Rules:             map[string]*tflint.RuleConfig{},
+				Plugins:           map[string]*tflint.PluginConfig{},
+			},
+		},
+		{
+			Name:    "--report-unused-annotations",
+			Command: "./tflint --report-unused-annotations",
+			Expected: &tflint.Config{
+				CallModuleType:             terraform.CallLocalModule,
+				Force:                      false,
+				IgnoreModules:              map[string]bool{},
+				Varfiles:                   []string{},
+				Variables:                  []string{},
+				ReportUnusedAnnotations:    true,
+				ReportUnusedAnnotationsSet: true,
+				DisabledByDefault:          false,
+				Rules:                      map[string]*tflint.RuleConfig{},
+				Plugins:                    map[string]*tflint.PluginConfig{},
+			},
+		},
 		{
 			Name:    "--force",
 			Command: "./tflint --force",
@@ -295,6 +357,11 @@ func Test_toWorkerCommands(t *testing.T) {
 				"--var-file=example2.tfvars",
 				"--var=foo=bar",
 				"--var=bar=baz",
+				"--workspace=dev",
+				"--workspace=prod",
+				"--sandbox-dir=/sandbox",
+				"--deterministic",
+				"--report-unused-annotations",
 				"--call-module-type=all",
 				"--chdir=dir",
 				"--recursive",
@@ -331,6 +398,11 @@ func Test_toWorkerCommands(t *testing.T) {
 				"--var-file=example2.tfvars",
 				"--var=foo=bar",
 				"--var=bar=baz",
+				"--workspace=dev",
+				"--workspace=prod",
+				"--sandbox-dir=/sandbox",
+				"--deterministic",
+				"--report-unused-annotations",
 				"--call-module-type=all",
 				"--chdir=subdir", // "--chdir=dir",
 				// "--recursive",
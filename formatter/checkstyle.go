@@ -37,7 +37,7 @@ func (f *Formatter) checkstylePrint(issues tflint.Issues, appErr error, sources
 			Line:     issue.Range.Start.Line,
 			Column:   issue.Range.Start.Column,
 			Severity: toSeverity(issue.Rule.Severity()),
-			Message:  issue.Message,
+			Message:  labeledMessage(issue),
 			Link:     issue.Rule.Link(),
 
 			Rule: issue.Rule.Name(),
@@ -39,6 +39,27 @@ func Test_checkstylePrint(t *testing.T) {
   <file name="test.tf">
     <error source="test_rule" line="1" column="1" severity="error" message="test" link="https://github.com" rule="test_rule"></error>
   </file>
+</checkstyle>`,
+		},
+		{
+			Name: "issues in multiple scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Scenarios: []string{"dev", "stg"},
+				},
+			},
+			Stdout: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle>
+  <file name="test.tf">
+    <error source="test_rule" line="1" column="1" severity="error" message="test [scenarios: dev, stg]" link="https://github.com" rule="test_rule"></error>
+  </file>
 </checkstyle>`,
 		},
 	}
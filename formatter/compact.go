@@ -21,7 +21,7 @@ func (f *Formatter) compactPrint(issues tflint.Issues, appErr error, sources map
 			issue.Range.Start.Line,
 			issue.Range.Start.Column,
 			issue.Rule.Severity(),
-			issue.Message,
+			labeledMessage(issue),
 			issue.Rule.Name(),
 		)
 	}
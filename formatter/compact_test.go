@@ -39,6 +39,26 @@ func Test_compactPrint(t *testing.T) {
 			Stdout: `1 issue(s) found:
 
 test.tf:1:1: Error - test (test_rule)
+`,
+		},
+		{
+			Name: "issues in multiple workspaces and scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Workspaces: []string{"dev", "prod"},
+					Scenarios:  []string{"small"},
+				},
+			},
+			Stdout: `1 issue(s) found:
+
+test.tf:1:1: Error - test [workspaces: dev, prod; scenarios: small] (test_rule)
 `,
 		},
 		{
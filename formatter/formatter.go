@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strings"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	sdk "github.com/nholuongut/tflint-plugin-sdk/tflint"
@@ -25,7 +26,12 @@ type Formatter struct {
 }
 
 // Print outputs the given issues and errors according to configured format
+// Issues suppressed by ignore blocks are output only in JSON format.
 func (f *Formatter) Print(issues tflint.Issues, err error, sources map[string][]byte) {
+	if f.Format != "json" {
+		issues = issues.Unsuppressed()
+	}
+
 	switch f.Format {
 	case "default":
 		f.prettyPrint(issues, err, sources)
@@ -81,6 +87,18 @@ func (f *Formatter) PrintParallel(issues tflint.Issues, sources map[string][]byt
 	return nil
 }
 
+// PrintWarnings outputs warning diagnostics, such as those found while loading configurations.
+// Unlike errors, warnings are output to stderr immediately regardless of the format,
+// as they do not affect the inspection results.
+func (f *Formatter) PrintWarnings(diags hcl.Diagnostics, sources map[string][]byte) {
+	if len(diags) == 0 {
+		return
+	}
+
+	writer := hcl.NewDiagnosticTextWriter(f.Stderr, parseSources(sources), 0, !f.NoColor)
+	_ = writer.WriteDiagnostics(diags)
+}
+
 func toSeverity(lintType tflint.Severity) string {
 	switch lintType {
 	case sdk.ERROR:
@@ -104,3 +122,19 @@ func fromHclSeverity(severity hcl.DiagnosticSeverity) string {
 		panic(fmt.Errorf("Unexpected HCL severity: %v", severity))
 	}
 }
+
+// labeledMessage returns the issue message with the workspaces and scenarios
+// where the issue was found, e.g. "message [workspaces: dev; scenarios: stg, prod]".
+func labeledMessage(issue *tflint.Issue) string {
+	labels := []string{}
+	if len(issue.Workspaces) > 0 {
+		labels = append(labels, "workspaces: "+strings.Join(issue.Workspaces, ", "))
+	}
+	if len(issue.Scenarios) > 0 {
+		labels = append(labels, "scenarios: "+strings.Join(issue.Scenarios, ", "))
+	}
+	if len(labels) == 0 {
+		return issue.Message
+	}
+	return fmt.Sprintf("%s [%s]", issue.Message, strings.Join(labels, "; "))
+}
@@ -170,3 +170,45 @@ Reference: https://github.com
 		})
 	}
 }
+
+func TestPrint_suppressed(t *testing.T) {
+	issues := tflint.Issues{
+		{
+			Rule:    &testRule{},
+			Message: "test",
+			Range: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+			},
+			Suppression: &tflint.Suppression{Reason: "generated code"},
+		},
+	}
+
+	tests := []struct {
+		format string
+		stdout string
+	}{
+		{
+			format: "compact",
+			stdout: "",
+		},
+		{
+			format: "json",
+			stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"suppression":{"reason":"generated code"}}],"errors":[]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			stdout := new(bytes.Buffer)
+			formatter := &Formatter{Stdout: stdout, Stderr: new(bytes.Buffer), Format: test.format}
+
+			formatter.Print(issues, nil, map[string][]byte{})
+
+			if diff := cmp.Diff(test.stdout, stdout.String()); diff != "" {
+				t.Errorf("diff: %s", diff)
+			}
+		})
+	}
+}
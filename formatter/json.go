@@ -16,6 +16,16 @@ type JSONIssue struct {
 	Message string      `json:"message"`
 	Range   JSONRange   `json:"range"`
 	Callers []JSONRange `json:"callers"`
+
+	Workspaces []string `json:"workspaces,omitempty"`
+	Scenarios  []string `json:"scenarios,omitempty"`
+
+	Suppression *JSONSuppression `json:"suppression,omitempty"`
+}
+
+// JSONSuppression is a temporary structure for converting suppressions to JSON.
+type JSONSuppression struct {
+	Reason string `json:"reason"`
 }
 
 // JSONRule is a temporary structure for converting TFLint rules to JSON.
@@ -69,6 +79,12 @@ func (f *Formatter) jsonPrint(issues tflint.Issues, appErr error) {
 				End:      JSONPos{Line: issue.Range.End.Line, Column: issue.Range.End.Column},
 			},
 			Callers: make([]JSONRange, len(issue.Callers)),
+
+			Workspaces: issue.Workspaces,
+			Scenarios:  issue.Scenarios,
+		}
+		if issue.Suppression != nil {
+			ret.Issues[idx].Suppression = &JSONSuppression{Reason: issue.Suppression.Reason}
 		}
 		for i, caller := range issue.Callers {
 			ret.Issues[idx].Callers[i] = JSONRange{
@@ -22,6 +22,39 @@ func Test_jsonPrint(t *testing.T) {
 			Issues: tflint.Issues{},
 			Stdout: `{"issues":[],"errors":[]}`,
 		},
+		{
+			Name: "issues in multiple workspaces and scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Workspaces: []string{"dev", "prod"},
+					Scenarios:  []string{"small"},
+				},
+			},
+			Stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"workspaces":["dev","prod"],"scenarios":["small"]}],"errors":[]}`,
+		},
+		{
+			Name: "suppressed issues",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Suppression: &tflint.Suppression{Reason: "generated code"},
+				},
+			},
+			Stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"suppression":{"reason":"generated code"}}],"errors":[]}`,
+		},
 		{
 			Name:   "error",
 			Error:  fmt.Errorf("Failed to work; %w", errors.New("I don't feel like working")),
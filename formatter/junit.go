@@ -14,17 +14,18 @@ func (f *Formatter) junitPrint(issues tflint.Issues, appErr error, sources map[s
 	cases := make([]formatter.JUnitTestCase, len(issues))
 
 	for i, issue := range issues.Sort() {
+		message := labeledMessage(issue)
 		cases[i] = formatter.JUnitTestCase{
 			Name:      issue.Rule.Name(),
 			Classname: issue.Range.Filename,
 			Time:      "0",
 			Failure: &formatter.JUnitFailure{
-				Message: fmt.Sprintf("%s: %s", issue.Range, issue.Message),
+				Message: fmt.Sprintf("%s: %s", issue.Range, message),
 				Type:    issue.Rule.Severity().String(),
 				Contents: fmt.Sprintf(
 					"%s: %s\nRule: %s\nRange: %s",
 					issue.Rule.Severity(),
-					issue.Message,
+					message,
 					issue.Rule.Name(),
 					issue.Range,
 				),
@@ -47,6 +47,30 @@ func Test_junitPrint(t *testing.T) {
       <failure message="test.tf:1,1-4: issue message" type="Error">Error: issue message&#xA;Rule: test_rule&#xA;Range: test.tf:1,1-4</failure>
     </testcase>
   </testsuite>
+</testsuites>`,
+		},
+		{
+			Name: "issues in multiple scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "issue message",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Scenarios: []string{"prod"},
+				},
+			},
+			Stdout: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite tests="1" failures="1" time="0" name="">
+    <properties></properties>
+    <testcase classname="test.tf" name="test_rule" time="0">
+      <failure message="test.tf:1,1-4: issue message [scenarios: prod]" type="Error">Error: issue message [scenarios: prod]&#xA;Rule: test_rule&#xA;Range: test.tf:1,1-4</failure>
+    </testcase>
+  </testsuite>
 </testsuites>`,
 		},
 	}
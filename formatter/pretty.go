@@ -95,6 +95,16 @@ func (f *Formatter) prettyPrintIssueWithSource(issue *tflint.Issue, sources map[
 		}
 	}
 
+	if len(issue.Workspaces) > 0 || len(issue.Scenarios) > 0 {
+		fmt.Fprint(f.Stdout, "\n")
+		if len(issue.Workspaces) > 0 {
+			fmt.Fprintf(f.Stdout, "Workspaces: %s\n", strings.Join(issue.Workspaces, ", "))
+		}
+		if len(issue.Scenarios) > 0 {
+			fmt.Fprintf(f.Stdout, "Scenarios: %s\n", strings.Join(issue.Scenarios, ", "))
+		}
+	}
+
 	if issue.Rule.Link() != "" {
 		fmt.Fprintf(f.Stdout, "\nReference: %s\n", issue.Rule.Link())
 	}
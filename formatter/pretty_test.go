@@ -98,6 +98,38 @@ Error: test (test_rule)
 
 Reference: https://github.com
 
+`,
+		},
+		{
+			Name: "issues in multiple workspaces and scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Workspaces: []string{"dev", "prod"},
+					Scenarios:  []string{"small"},
+				},
+			},
+			Sources: map[string][]byte{
+				"test.tf": []byte("foo = 1"),
+			},
+			Stdout: `1 issue(s) found:
+
+Error: test (test_rule)
+
+  on test.tf line 1:
+   1: foo = 1
+
+Workspaces: dev, prod
+Scenarios: small
+
+Reference: https://github.com
+
 `,
 		},
 		{
@@ -64,6 +64,17 @@ func (f *Formatter) sarifPrint(issues tflint.Issues, appErr error) {
 		if location != nil {
 			result.AddLocation(sarif.NewLocationWithPhysicalLocation(location))
 		}
+
+		if len(issue.Workspaces) > 0 || len(issue.Scenarios) > 0 {
+			properties := sarif.NewPropertyBag()
+			if len(issue.Workspaces) > 0 {
+				properties.Add("workspaces", issue.Workspaces)
+			}
+			if len(issue.Scenarios) > 0 {
+				properties.Add("scenarios", issue.Scenarios)
+			}
+			result.AttachPropertyBag(properties)
+		}
 	}
 
 	errRun := sarif.NewRunWithInformationURI("tflint-errors", "https://github.com/nholuongut/tflint")
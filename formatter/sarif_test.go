@@ -125,6 +125,91 @@ func Test_sarifPrint(t *testing.T) {
       "results": []
     }
   ]
+}`, tflint.Version, tflint.Version),
+		},
+		{
+			Name: "issues in multiple workspaces and scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Workspaces: []string{"dev"},
+					Scenarios:  []string{"stg", "prod"},
+				},
+			},
+			Stdout: fmt.Sprintf(`{
+  "version": "2.1.0",
+  "$schema": "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/main/sarif-2.1/schema/sarif-schema-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "informationUri": "https://github.com/nholuongut/tflint",
+          "name": "tflint",
+          "rules": [
+            {
+              "id": "test_rule",
+              "shortDescription": {
+                "text": ""
+              },
+              "helpUri": "https://github.com"
+            }
+          ],
+          "version": "%s"
+        }
+      },
+      "results": [
+        {
+          "properties": {
+            "scenarios": [
+              "stg",
+              "prod"
+            ],
+            "workspaces": [
+              "dev"
+            ]
+          },
+          "ruleId": "test_rule",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "test"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "test.tf"
+                },
+                "region": {
+                  "startLine": 1,
+                  "startColumn": 1,
+                  "endLine": 1,
+                  "endColumn": 4
+                }
+              }
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "tool": {
+        "driver": {
+          "informationUri": "https://github.com/nholuongut/tflint",
+          "name": "tflint-errors",
+          "rules": [],
+          "version": "%s"
+        }
+      },
+      "results": []
+    }
+  ]
 }`, tflint.Version, tflint.Version),
 		},
 		{
@@ -167,20 +167,20 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	if err != nil {
 		return ret, fmt.Errorf("Failed to prepare loading: %w", err)
 	}
+	loader.SetLanguage(h.config.Language)
 
 	configs, diags := loader.LoadConfig(".", h.config.CallModuleType)
 	if diags.HasErrors() {
 		return ret, fmt.Errorf("Failed to load configurations: %w", diags)
 	}
+	// Diagnostics without errors are warnings, such as modules that need to be reinstalled
+	warnings := diags
 	files, diags := loader.LoadConfigDirFiles(".")
 	if diags.HasErrors() {
 		return ret, fmt.Errorf("Failed to load configurations: %w", diags)
 	}
 	annotations := map[string]tflint.Annotations{}
 	for path, file := range files {
-		if !strings.HasSuffix(path, ".tf") {
-			continue
-		}
 		ants, lexDiags := tflint.NewAnnotations(path, file)
 		diags = diags.Extend(lexDiags)
 		annotations[path] = ants
@@ -206,6 +206,10 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	}
 	runners = append(runners, runner)
 
+	for _, r := range runners {
+		r.CheckConditions()
+	}
+
 	config := h.config.ToPluginConfig()
 	for name, ruleset := range h.plugin.RuleSets {
 		if err := ruleset.ApplyGlobalConfig(config); err != nil {
@@ -243,7 +247,7 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	h.diagsPaths = []string{}
 
 	for _, runner := range runners {
-		for _, issue := range runner.LookupIssues() {
+		for _, issue := range runner.LookupIssues().Unsuppressed() {
 			path := filepath.Join(h.rootDir, issue.Range.Filename)
 			h.diagsPaths = append(h.diagsPaths, path)
 
@@ -264,6 +268,24 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 		}
 	}
 
+	for _, warning := range warnings {
+		if warning.Subject == nil {
+			continue
+		}
+		path := filepath.Join(h.rootDir, warning.Subject.Filename)
+		h.diagsPaths = append(h.diagsPaths, path)
+
+		diag := lsp.Diagnostic{
+			Message:  fmt.Sprintf("%s; %s", warning.Summary, warning.Detail),
+			Severity: lsp.Warning,
+			Range: lsp.Range{
+				Start: lsp.Position{Line: warning.Subject.Start.Line - 1, Character: warning.Subject.Start.Column - 1},
+				End:   lsp.Position{Line: warning.Subject.End.Line - 1, Character: warning.Subject.End.Column - 1},
+			},
+		}
+		ret[path] = append(ret[path], diag)
+	}
+
 	return ret, nil
 }
 
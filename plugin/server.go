@@ -138,7 +138,7 @@ func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOpti
 		runner = s.rootRunner
 	}
 
-	val, diags := runner.Ctx.EvaluateExpr(expr, *opts.WantType)
+	val, diags := runner.EvaluateExpr(expr, *opts.WantType)
 	if diags.HasErrors() {
 		return val, diags
 	}
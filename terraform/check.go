@@ -0,0 +1,91 @@
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/nholuongut/tflint-plugin-sdk/hclext"
+)
+
+// CheckRule represents a configuration-defined validation rule, precondition,
+// postcondition or assertion.
+type CheckRule struct {
+	// Condition is an expression that must evaluate to true if the
+	// condition holds or false if it does not.
+	Condition hcl.Expression
+
+	// ErrorMessage is an expression that should evaluate to a string
+	// describing the problem when the condition does not hold.
+	ErrorMessage hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+func decodeCheckRuleBlock(block *hclext.Block) *CheckRule {
+	cr := &CheckRule{
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := block.Body.Attributes["condition"]; exists {
+		cr.Condition = attr.Expr
+	}
+
+	if attr, exists := block.Body.Attributes["error_message"]; exists {
+		cr.ErrorMessage = attr.Expr
+	}
+
+	return cr
+}
+
+var checkRuleBlockSchema = &hclext.BodySchema{
+	Attributes: []hclext.AttributeSchema{
+		{
+			Name: "condition",
+		},
+		{
+			Name: "error_message",
+		},
+	},
+}
+
+// Check represents a "check" block.
+type Check struct {
+	Name string
+
+	// DataResource is a scoped data source that is only available in the check block.
+	// This is nil if no data source is declared.
+	DataResource *Resource
+	Asserts      []*CheckRule
+
+	DeclRange hcl.Range
+}
+
+func decodeCheckBlock(block *hclext.Block) *Check {
+	c := &Check{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	for _, b := range block.Body.Blocks {
+		switch b.Type {
+		case "data":
+			c.DataResource = decodeDataBlock(b)
+		case "assert":
+			c.Asserts = append(c.Asserts, decodeCheckRuleBlock(b))
+		}
+	}
+
+	return c
+}
+
+var checkBlockSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+			Body:       resourceBlockSchema,
+		},
+		{
+			Type: "assert",
+			Body: checkRuleBlockSchema,
+		},
+	},
+}
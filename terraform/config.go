@@ -96,11 +96,13 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 		}
 
 		req := ModuleRequest{
-			Name:       call.Name,
-			Path:       path,
-			SourceAddr: call.SourceAddr,
-			Parent:     parent,
-			CallRange:  call.DeclRange,
+			Name:              call.Name,
+			Path:              path,
+			SourceAddr:        call.SourceAddr,
+			SourceAddrRange:   call.SourceAddrRange,
+			VersionConstraint: call.Version,
+			Parent:            parent,
+			CallRange:         call.DeclRange,
 		}
 
 		mod, _, modDiags := walker.LoadModule(&req)
@@ -186,6 +188,14 @@ type ModuleRequest struct {
 	// configuration.
 	SourceAddr addrs.ModuleSource
 
+	// SourceAddrRange is the source range for the SourceAddr value as it
+	// was provided in configuration.
+	SourceAddrRange hcl.Range
+
+	// VersionConstraint is the version constraint applied to the module in
+	// configuration. This is only meaningful for registry modules.
+	VersionConstraint VersionConstraint
+
 	// Parent is the partially-constructed module tree node that the loaded
 	// module will be added to. Callers may refer to any field of this
 	// structure except Children, which is still under construction when
@@ -2,10 +2,13 @@ package terraform
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/agext/levenshtein"
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
 	"github.com/nholuongut/tflint-plugin-sdk/terraform/lang/marks"
@@ -13,6 +16,7 @@ import (
 	"github.com/nholuongut/tflint/terraform/lang"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 type ContextMeta struct {
@@ -25,6 +29,18 @@ type Evaluator struct {
 	ModulePath     addrs.ModuleInstance
 	Config         *Config
 	VariableValues map[string]map[string]cty.Value
+
+	// SandboxDir is the directory that filesystem functions can access.
+	// If empty, filesystem functions can access any paths.
+	SandboxDir string
+
+	// DeterministicResults are the fixed results of impure functions.
+	// If nil, impure functions return actual results or unknown values.
+	DeterministicResults map[string]string
+
+	// TerraformVersion is the Terraform version targeted by the configuration.
+	// If nil, all functions are available.
+	TerraformVersion *version.Version
 }
 
 // EvaluateExpr takes the given HCL expression and evaluates it to produce a value.
@@ -47,17 +63,60 @@ func (e *Evaluator) ExpandBlock(body hcl.Body, schema *hclext.BodySchema) (hcl.B
 	return e.scope().ExpandBlock(body, schema)
 }
 
+// EvaluateCheckRule evaluates the condition of the given rule, such as a variable validation.
+// It returns false and the evaluated error message only if the condition is known to fail.
+// If the condition cannot be determined, such as when it contains unknown values,
+// it is considered to hold since there is no evidence of failure in static analysis.
+//
+// If the error message cannot be evaluated, the returned message will be empty.
+// Callers should provide a generic message in that case.
+func (e *Evaluator) EvaluateCheckRule(rule *CheckRule) (bool, string, hcl.Diagnostics) {
+	if rule.Condition == nil {
+		return true, "", nil
+	}
+
+	result, diags := e.EvaluateExpr(rule.Condition, cty.Bool)
+	if diags.HasErrors() {
+		return true, "", diags
+	}
+	result, _ = result.Unmark()
+	if !result.IsWhollyKnown() || result.IsNull() || result.True() {
+		return true, "", nil
+	}
+
+	if rule.ErrorMessage == nil {
+		return false, "", nil
+	}
+	msg, msgDiags := e.EvaluateExpr(rule.ErrorMessage, cty.String)
+	if msgDiags.HasErrors() {
+		log.Printf("[DEBUG] Failed to evaluate error message: %s", msgDiags)
+		return false, "", nil
+	}
+	// Sensitive error messages are not exposed, just like Terraform.
+	if !msg.IsWhollyKnown() || msg.IsNull() || msg.IsMarked() {
+		return false, "", nil
+	}
+	return false, msg.AsString(), nil
+}
+
 // scope creates a new evaluation scope.
 // The difference with Evaluator is that each evaluation is independent
 // and is not shared between goroutines.
 func (e *Evaluator) scope() *lang.Scope {
-	scope := &lang.Scope{CallStack: lang.NewCallStack()}
+	scope := &lang.Scope{
+		CallStack:            lang.NewCallStack(),
+		SandboxDir:           e.SandboxDir,
+		DeterministicResults: e.DeterministicResults,
+		TerraformVersion:     e.TerraformVersion,
+	}
 	scope.Data = &evaluationData{
 		Scope:          scope,
 		Meta:           e.Meta,
 		ModulePath:     e.ModulePath,
+		ModuleInstance: e.ModulePath,
 		Config:         e.Config,
 		VariableValues: e.VariableValues,
+		ModuleCalls:    newModuleCallCache(),
 	}
 	return scope
 }
@@ -68,10 +127,62 @@ type evaluationData struct {
 	ModulePath     addrs.ModuleInstance
 	Config         *Config
 	VariableValues map[string]map[string]cty.Value
+
+	// ModuleInstance is the address of the module instance being evaluated.
+	// Unlike ModulePath, it includes the instance keys of the module calls.
+	ModuleInstance addrs.ModuleInstance
+
+	// ModuleCalls memoizes the outputs of module call instances.
+	// It is shared by all scopes created during an evaluation.
+	ModuleCalls *moduleCallCache
+
+	// InstanceKeyData is the count.index/each.key/each.value bound while
+	// evaluating the arguments of a particular module call instance.
+	// These are zero values in any other contexts.
+	InstanceKeyData instanceKeyEvalData
+}
+
+// instanceKeyEvalData is the data for count.index/each.key/each.value.
+type instanceKeyEvalData struct {
+	CountIndex cty.Value
+	EachKey    cty.Value
+	EachValue  cty.Value
 }
 
 var _ lang.Data = (*evaluationData)(nil)
 
+// instanceKey returns the instance key of the module call or resource instance.
+func (k instanceKeyEvalData) instanceKey() addrs.InstanceKey {
+	switch {
+	case k.CountIndex != cty.NilVal:
+		idx, _ := k.CountIndex.AsBigFloat().Int64()
+		return addrs.IntKey(idx)
+	case k.EachKey != cty.NilVal:
+		return addrs.StringKey(k.EachKey.AsString())
+	default:
+		return addrs.NoKey
+	}
+}
+
+// moduleCallCache memoizes the outputs of module call instances during an evaluation,
+// so that referring to a module call multiple times does not evaluate the called module again.
+type moduleCallCache struct {
+	values map[string]moduleCallResult
+
+	// cuts is the number of references resolved as unknown to break circular references.
+	// Results depending on them vary with the evaluation order, so they are not memoized.
+	cuts int
+}
+
+type moduleCallResult struct {
+	value cty.Value
+	diags hcl.Diagnostics
+}
+
+func newModuleCallCache() *moduleCallCache {
+	return &moduleCallCache{values: map[string]moduleCallResult{}}
+}
+
 func (d *evaluationData) GetCountAttr(addr addrs.CountAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	// Note that the actual evaluation of count.index is not done here.
 	// count.index is already evaluated when expanded by ExpandBlock,
@@ -80,6 +191,12 @@ func (d *evaluationData) GetCountAttr(addr addrs.CountAttr, rng hcl.Range) (cty.
 	// Although, there are cases where count.index is evaluated as-is,
 	// such as when not expanding the body. In that case, evaluate it
 	// as an unknown and skip further checks.
+	//
+	// The exception is when evaluating module call arguments to resolve
+	// module outputs. In that case, the instance key is bound here.
+	if addr.Name == "index" && d.InstanceKeyData.CountIndex != cty.NilVal {
+		return d.InstanceKeyData.CountIndex, nil
+	}
 	return cty.UnknownVal(cty.Number), nil
 }
 
@@ -91,6 +208,15 @@ func (d *evaluationData) GetForEachAttr(addr addrs.ForEachAttr, rng hcl.Range) (
 	// Although, there are cases where each.key/each.value is evaluated as-is,
 	// such as when not expanding the body. In that case, evaluate it
 	// as an unknown and skip further checks.
+	//
+	// The exception is when evaluating module call arguments to resolve
+	// module outputs. In that case, the instance key is bound here.
+	switch {
+	case addr.Name == "key" && d.InstanceKeyData.EachKey != cty.NilVal:
+		return d.InstanceKeyData.EachKey, nil
+	case addr.Name == "value" && d.InstanceKeyData.EachValue != cty.NilVal:
+		return d.InstanceKeyData.EachValue, nil
+	}
 	return cty.DynamicVal, nil
 }
 
@@ -140,27 +266,12 @@ func (d *evaluationData) GetInputVariable(addr addrs.InputVariable, rng hcl.Rang
 	// This has some restrictions on the representation of dynamic variables compared
 	// to Terraform, but since TFLint is intended for static analysis, this is often enough.
 	val, isSet := vals[addr.Name]
-	switch {
-	case !isSet:
+	if !isSet {
 		// The config loader will ensure there is a default if the value is not
 		// set at all.
 		val = config.Default
-
-	case val.IsNull() && !config.Nullable && config.Default != cty.NilVal:
-		// If nullable=false a null value will use the configured default.
-		val = config.Default
 	}
-
-	// Apply defaults from the variable's type constraint to the value,
-	// unless the value is null. We do not apply defaults to top-level
-	// null values, as doing so could prevent assigning null to a nullable
-	// variable.
-	if config.TypeDefaults != nil && !val.IsNull() {
-		val = config.TypeDefaults.Apply(val)
-	}
-
-	var err error
-	val, err = convert.Convert(val, config.ConstraintType)
+	val, err := finalVariableValue(config, val)
 	if err != nil {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -171,14 +282,34 @@ func (d *evaluationData) GetInputVariable(addr addrs.InputVariable, rng hcl.Rang
 		val = cty.UnknownVal(config.Type)
 	}
 
-	// Mark if sensitive
-	if config.Sensitive {
+	// Mark if sensitive. Ephemeral variables are also treated like sensitive
+	// variables, as they are usually secrets that are never persisted.
+	if config.Sensitive || config.Ephemeral {
 		val = val.Mark(marks.Sensitive)
 	}
 
 	return val, diags
 }
 
+// finalVariableValue applies the default and the type conversion to the given
+// value of the variable, as Terraform does before the value is referenced.
+func finalVariableValue(config *Variable, val cty.Value) (cty.Value, error) {
+	if val.IsNull() && !config.Nullable && config.Default != cty.NilVal {
+		// If nullable=false a null value will use the configured default.
+		val = config.Default
+	}
+
+	// Apply defaults from the variable's type constraint to the value,
+	// unless the value is null. We do not apply defaults to top-level
+	// null values, as doing so could prevent assigning null to a nullable
+	// variable.
+	if config.TypeDefaults != nil && !val.IsNull() {
+		val = config.TypeDefaults.Apply(val)
+	}
+
+	return convert.Convert(val, config.ConstraintType)
+}
+
 func (d *evaluationData) GetLocalValue(addr addrs.LocalValue, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -222,6 +353,260 @@ func (d *evaluationData) GetLocalValue(addr addrs.LocalValue, rng hcl.Range) (ct
 	return val, diags
 }
 
+func (d *evaluationData) GetModule(addr addrs.ModuleCall, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	// Output results live in the module that will be calling the module.
+	moduleConfig := d.Config.DescendentForInstance(d.ModulePath)
+	if moduleConfig == nil {
+		// should never happen, since we can't be evaluating in a module
+		// that wasn't mentioned in configuration.
+		panic(fmt.Sprintf("module call read from %s, which has no configuration", d.ModulePath))
+	}
+
+	callConfig := moduleConfig.Module.ModuleCalls[addr.Name]
+	childConfig := moduleConfig.Children[addr.Name]
+	if callConfig == nil || childConfig == nil {
+		// Unlike Terraform, references to undeclared module calls are not reported.
+		// Also, the called module may not be loaded, e.g. remote modules are not called
+		// or the module is ignored. In these cases, the outputs are unknown.
+		return cty.DynamicVal, nil
+	}
+
+	// Build a call stack for circular reference detection.
+	// Unlike local values, Terraform allows module calls to refer to each other's
+	// outputs as long as the individual inputs and outputs do not form a cycle.
+	// TFLint resolves all outputs at once, so such references are treated as unknown
+	// rather than reporting them as errors.
+	if diags := d.Scope.CallStack.Push(addrs.Reference{Subject: addr, SourceRange: rng}); diags.HasErrors() {
+		log.Printf("[DEBUG] %s", diags.Error())
+		d.ModuleCalls.cuts++
+		return cty.DynamicVal, nil
+	}
+
+	val, diags := d.moduleCallValue(callConfig, childConfig)
+
+	d.Scope.CallStack.Pop()
+	return val, diags
+}
+
+// moduleCallValue returns the value of the given module call that is referenced as `module.<name>`.
+// Each instance is an object of outputs.
+func (d *evaluationData) moduleCallValue(call *ModuleCall, child *Config) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	// Extract arguments that are passed as input variables of the child module.
+	// Note that expansion is not performed here because the evaluation of
+	// other module blocks is unnecessary and can cause circular references.
+	moduleCallSchema := &hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+				Body:       &hclext.BodySchema{},
+			},
+		},
+	}
+	for _, v := range child.Module.Variables {
+		moduleCallSchema.Blocks[0].Body.Attributes = append(moduleCallSchema.Blocks[0].Body.Attributes, hclext.AttributeSchema{Name: v.Name})
+	}
+	moduleConfig := d.Config.DescendentForInstance(d.ModulePath)
+	content, contentDiags := moduleConfig.Module.PartialContent(moduleCallSchema, nil)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	args := hclext.Attributes{}
+	for _, block := range content.Blocks {
+		if block.Labels[0] == call.Name {
+			args = block.Body.Attributes
+		}
+	}
+
+	return d.instancesValue(call.Count, call.ForEach, func(keyData instanceKeyEvalData) (cty.Value, hcl.Diagnostics) {
+		instance := append(slices.Clone(d.ModuleInstance), addrs.ModuleInstanceStep{Name: call.Name, InstanceKey: keyData.instanceKey()})
+		key := instance.String()
+		if result, exists := d.ModuleCalls.values[key]; exists {
+			return result.value, result.diags
+		}
+
+		cuts := d.ModuleCalls.cuts
+		val, diags := d.moduleCallInstanceValue(child, instance, args, keyData)
+		if cuts == d.ModuleCalls.cuts && !diags.HasErrors() {
+			d.ModuleCalls.values[key] = moduleCallResult{value: val, diags: diags}
+		}
+		return val, diags
+	})
+}
+
+// instancesValue returns the value of a resource or module call that is expanded by count/for_each.
+// Like Terraform, the value is an instance value if neither count nor for_each is set,
+// a tuple of instances if count is set, and an object of instances keyed by for_each keys.
+// If the instances cannot be determined, this returns an unknown value.
+func (d *evaluationData) instancesValue(countExpr hcl.Expression, forEachExpr hcl.Expression, instanceValue func(instanceKeyEvalData) (cty.Value, hcl.Diagnostics)) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	switch {
+	case countExpr != nil:
+		countVal, countDiags := d.Scope.EvalExpr(countExpr, cty.Number)
+		diags = diags.Extend(countDiags)
+		if countDiags.HasErrors() || !countVal.IsWhollyKnown() || countVal.IsNull() || countVal.IsMarked() {
+			return cty.DynamicVal, diags
+		}
+		var count int
+		if err := gocty.FromCtyValue(countVal, &count); err != nil || count < 0 {
+			return cty.DynamicVal, diags
+		}
+
+		instances := make([]cty.Value, count)
+		for idx := 0; idx < count; idx++ {
+			instance, instanceDiags := instanceValue(instanceKeyEvalData{
+				CountIndex: cty.NumberIntVal(int64(idx)),
+			})
+			diags = diags.Extend(instanceDiags)
+			if instanceDiags.HasErrors() {
+				return cty.DynamicVal, diags
+			}
+			instances[idx] = instance
+		}
+		return cty.TupleVal(instances), diags
+
+	case forEachExpr != nil:
+		forEachVal, forEachDiags := d.Scope.EvalExpr(forEachExpr, cty.DynamicPseudoType)
+		diags = diags.Extend(forEachDiags)
+		if forEachDiags.HasErrors() || !forEachVal.IsKnown() || forEachVal.IsNull() || forEachVal.ContainsMarked() {
+			return cty.DynamicVal, diags
+		}
+		ty := forEachVal.Type()
+		if !(ty.IsMapType() || ty.IsObjectType() || (ty.IsSetType() && forEachVal.IsWhollyKnown())) {
+			return cty.DynamicVal, diags
+		}
+
+		instances := map[string]cty.Value{}
+		for it := forEachVal.ElementIterator(); it.Next(); {
+			key, value := it.Element()
+			if ty.IsSetType() {
+				key = value
+			}
+			key, err := convert.Convert(key, cty.String)
+			if err != nil || key.IsNull() {
+				return cty.DynamicVal, diags
+			}
+
+			instance, instanceDiags := instanceValue(instanceKeyEvalData{
+				EachKey:   key,
+				EachValue: value,
+			})
+			diags = diags.Extend(instanceDiags)
+			if instanceDiags.HasErrors() {
+				return cty.DynamicVal, diags
+			}
+			instances[key.AsString()] = instance
+		}
+		return cty.ObjectVal(instances), diags
+
+	default:
+		return instanceValue(instanceKeyEvalData{})
+	}
+}
+
+// instanceScope returns a scope for evaluating expressions in a resource or
+// module call instance. The call stack is shared with the receiver because
+// the expressions are evaluated in the same module.
+func (d *evaluationData) instanceScope(keyData instanceKeyEvalData) *lang.Scope {
+	scope := &lang.Scope{
+		CallStack:            d.Scope.CallStack,
+		SandboxDir:           d.Scope.SandboxDir,
+		DeterministicResults: d.Scope.DeterministicResults,
+		TerraformVersion:     d.Scope.TerraformVersion,
+	}
+	scope.Data = &evaluationData{
+		Scope:           scope,
+		Meta:            d.Meta,
+		ModulePath:      d.ModulePath,
+		ModuleInstance:  d.ModuleInstance,
+		Config:          d.Config,
+		VariableValues:  d.VariableValues,
+		ModuleCalls:     d.ModuleCalls,
+		InstanceKeyData: keyData,
+	}
+	return scope
+}
+
+// moduleCallInstanceValue evaluates the outputs of a module call instance.
+// The passed arguments are evaluated in the calling module with the instance key,
+// and the outputs are evaluated in the called module using them as input variables.
+//
+// Like Terraform, the arguments are converted to the type constraints of the variables.
+// Arguments that cannot be converted are reported by the module_input rule, so they
+// are treated as unknown values here.
+func (d *evaluationData) moduleCallInstanceValue(child *Config, instance addrs.ModuleInstance, args hclext.Attributes, keyData instanceKeyEvalData) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	parentScope := d.instanceScope(keyData)
+
+	inputs := InputValues{}
+	for name, attr := range args {
+		val, valDiags := parentScope.EvalExpr(attr.Expr, cty.DynamicPseudoType)
+		diags = diags.Extend(valDiags)
+		if valDiags.HasErrors() {
+			return cty.DynamicVal, diags
+		}
+
+		variable := child.Module.Variables[name]
+		converted, err := finalVariableValue(variable, val)
+		if err != nil {
+			log.Printf("[DEBUG] Failed to convert the argument %q of %s: %s", name, instance, err)
+			converted = cty.UnknownVal(variable.ConstraintType)
+		}
+		inputs[name] = &InputValue{Value: converted}
+	}
+
+	// Unlike the root module, environment variables are not applied to child modules.
+	moduleKey := child.Path.UnkeyedInstanceShim().String()
+	variableValues := map[string]map[string]cty.Value{moduleKey: {}}
+	for name, iv := range DefaultVariableValues(child.Module.Variables).Override(inputs) {
+		variableValues[moduleKey][name] = iv.Value
+	}
+
+	// The called module has its own namespace, so the call stack is not shared.
+	childScope := &lang.Scope{
+		CallStack:            lang.NewCallStack(),
+		SandboxDir:           d.Scope.SandboxDir,
+		DeterministicResults: d.Scope.DeterministicResults,
+		TerraformVersion:     d.Scope.TerraformVersion,
+	}
+	childScope.Data = &evaluationData{
+		Scope:          childScope,
+		Meta:           d.Meta,
+		ModulePath:     child.Path.UnkeyedInstanceShim(),
+		ModuleInstance: instance,
+		Config:         d.Config,
+		VariableValues: variableValues,
+		ModuleCalls:    d.ModuleCalls,
+	}
+
+	outputs := make(map[string]cty.Value, len(child.Module.Outputs))
+	for name, output := range child.Module.Outputs {
+		if output.Expr == nil {
+			outputs[name] = cty.DynamicVal
+			continue
+		}
+
+		val, valDiags := childScope.EvalExpr(output.Expr, cty.DynamicPseudoType)
+		if valDiags.HasErrors() {
+			// Errors in the called module are not caused by the caller's expression,
+			// so they are not reported here. The output is treated as unknown.
+			log.Printf("[DEBUG] Failed to evaluate output %q in %s: %s", name, child.Path, valDiags.Error())
+			val = cty.DynamicVal
+		}
+		if output.Sensitive || output.Ephemeral {
+			val = val.Mark(marks.Sensitive)
+		}
+		outputs[name] = val
+	}
+
+	return cty.ObjectVal(outputs), diags
+}
+
 func (d *evaluationData) GetPathAttr(addr addrs.PathAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	switch addr.Name {
@@ -291,6 +676,132 @@ func (d *evaluationData) GetPathAttr(addr addrs.PathAttr, rng hcl.Range) (cty.Va
 	}
 }
 
+// GetResource returns the value of the given resource. Since TFLint does not know
+// resource schemas, only the attributes literally set in the configuration can be
+// resolved, and the other attributes, including computed attributes, are unknown.
+// Only the given attributes are included in the resulting object.
+func (d *evaluationData) GetResource(addr addrs.Resource, attrs []string, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	moduleConfig := d.Config.DescendentForInstance(d.ModulePath)
+	if moduleConfig == nil {
+		// should never happen, since we can't be evaluating in a module
+		// that wasn't mentioned in configuration.
+		panic(fmt.Sprintf("resource read from %s, which has no configuration", d.ModulePath))
+	}
+
+	var resources map[string]map[string]*Resource
+	switch addr.Mode {
+	case addrs.ManagedResourceMode:
+		resources = moduleConfig.Module.Resources
+	case addrs.DataResourceMode:
+		resources = moduleConfig.Module.DataResources
+	case addrs.EphemeralResourceMode:
+		resources = moduleConfig.Module.EphemeralResources
+	default:
+		panic(fmt.Sprintf("unexpected resource mode: %s", addr.Mode))
+	}
+
+	rc := resources[addr.Type][addr.Name]
+	if rc == nil {
+		// Unlike Terraform, references to undeclared resources are not reported.
+		return cty.DynamicVal, nil
+	}
+	if attrs == nil {
+		// The whole resource object cannot be built without the resource schema.
+		return cty.DynamicVal, nil
+	}
+
+	// Build a call stack for circular reference detection.
+	// Terraform reports circular references between resources as errors,
+	// but TFLint treats them as unknown because it is not the concern of
+	// the expression being evaluated.
+	if diags := d.Scope.CallStack.Push(addrs.Reference{Subject: addr, SourceRange: rng}); diags.HasErrors() {
+		log.Printf("[DEBUG] %s", diags.Error())
+		d.ModuleCalls.cuts++
+		return cty.DynamicVal, nil
+	}
+
+	val, diags := d.resourceValue(rc, attrs)
+
+	d.Scope.CallStack.Pop()
+
+	// Ephemeral values are never persisted, so they are treated like sensitive values.
+	if addr.Mode == addrs.EphemeralResourceMode {
+		val = val.Mark(marks.Sensitive)
+	}
+	return val, diags
+}
+
+// resourceValue returns the value of the given resource. Each instance is
+// an object of the given attributes.
+func (d *evaluationData) resourceValue(rc *Resource, attrs []string) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	blockType := "resource"
+	switch rc.Mode {
+	case addrs.DataResourceMode:
+		blockType = "data"
+	case addrs.EphemeralResourceMode:
+		blockType = "ephemeral"
+	}
+	resourceSchema := &hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type:       blockType,
+				LabelNames: []string{"type", "name"},
+				Body:       &hclext.BodySchema{},
+			},
+		},
+	}
+	for _, attr := range attrs {
+		if resourceMetaArguments[attr] {
+			continue
+		}
+		resourceSchema.Blocks[0].Body.Attributes = append(resourceSchema.Blocks[0].Body.Attributes, hclext.AttributeSchema{Name: attr})
+	}
+	moduleConfig := d.Config.DescendentForInstance(d.ModulePath)
+	content, contentDiags := moduleConfig.Module.PartialContent(resourceSchema, nil)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	args := hclext.Attributes{}
+	for _, block := range content.Blocks {
+		if block.Labels[0] == rc.Type && block.Labels[1] == rc.Name {
+			args = block.Body.Attributes
+		}
+	}
+
+	return d.instancesValue(rc.Count, rc.ForEach, func(keyData instanceKeyEvalData) (cty.Value, hcl.Diagnostics) {
+		return d.resourceInstanceValue(args, attrs, keyData)
+	})
+}
+
+// resourceInstanceValue evaluates the given attributes of a resource instance.
+// Attributes that are not set in the configuration are unknown.
+func (d *evaluationData) resourceInstanceValue(args hclext.Attributes, attrs []string, keyData instanceKeyEvalData) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	scope := d.instanceScope(keyData)
+
+	vals := make(map[string]cty.Value, len(attrs))
+	for _, name := range attrs {
+		attr, exists := args[name]
+		if !exists {
+			vals[name] = cty.DynamicVal
+			continue
+		}
+
+		val, valDiags := scope.EvalExpr(attr.Expr, cty.DynamicPseudoType)
+		diags = diags.Extend(valDiags)
+		if valDiags.HasErrors() {
+			return cty.DynamicVal, diags
+		}
+		vals[name] = val
+	}
+
+	return cty.ObjectVal(vals), diags
+}
+
 func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	switch addr.Name {
@@ -299,6 +810,11 @@ func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Rang
 		workspaceName := d.Meta.Env
 		return cty.StringVal(workspaceName), diags
 
+	case "applying":
+		// Terraform returns true only during the apply phase. TFLint inspects
+		// the configuration apart from both plan and apply, so it is unknown.
+		return cty.UnknownVal(cty.Bool), diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -315,7 +831,7 @@ func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Rang
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "terraform" attribute`,
-			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attribute is terraform.workspace, the name of the currently-selected workspace.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The supported attributes are terraform.workspace, the name of the currently-selected workspace, and terraform.applying, whether Terraform is applying the changes.`, addr.Name),
 			Subject:  rng.Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -2,8 +2,10 @@ package terraform
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -14,6 +16,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
 	"github.com/nholuongut/tflint-plugin-sdk/terraform/lang/marks"
+	"github.com/nholuongut/tflint/terraform/addrs"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -170,6 +173,25 @@ variable "string_var" {
 			want:     `cty.StringVal("default")`,
 			errCheck: neverHappend,
 		},
+		{
+			name:     "terraform applying",
+			expr:     expr(`terraform.applying ? "apply" : "plan"`),
+			ty:       cty.String,
+			want:     `cty.UnknownVal(cty.String).RefineNotNull()`,
+			errCheck: neverHappend,
+		},
+		{
+			name: "ephemeral variable",
+			config: `
+variable "token" {
+  ephemeral = true
+  default   = "secret"
+}`,
+			expr:     expr(`var.token`),
+			ty:       cty.String,
+			want:     `cty.StringVal("secret").Mark(marks.Sensitive)`,
+			errCheck: neverHappend,
+		},
 		{
 			name: "interpolation in string",
 			config: `
@@ -1214,6 +1236,103 @@ resource "aws_instance" "main" {
 				},
 			},
 		},
+		{
+			name: "for_each is a for expression with unknown values",
+			config: `
+resource "aws_instance" "main" {
+  for_each = { for k in ["foo", "bar"] : k => module.meta.ids[k] }
+  value    = [each.key, each.value]
+}`,
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "resource", LabelNames: []string{"type", "name"}, Body: &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}}},
+				},
+			},
+			want: &hclext.BodyContent{
+				Attributes: hclext.Attributes{},
+				Blocks: hclext.Blocks{
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body:   &hclext.BodyContent{Attributes: hclext.Attributes{"value": {Name: "value", Expr: hcl.StaticExpr(cty.TupleVal([]cty.Value{cty.StringVal("bar"), cty.DynamicVal}), hcl.Range{})}}, Blocks: hclext.Blocks{}},
+					},
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body:   &hclext.BodyContent{Attributes: hclext.Attributes{"value": {Name: "value", Expr: hcl.StaticExpr(cty.TupleVal([]cty.Value{cty.StringVal("foo"), cty.DynamicVal}), hcl.Range{})}}, Blocks: hclext.Blocks{}},
+					},
+				},
+			},
+		},
+		{
+			name: "for_each is a for expression with unknown conditions",
+			config: `
+resource "aws_instance" "main" {
+  for_each = { for k, v in { foo = true, bar = module.meta.enabled, baz = false } : k => v if v }
+  value    = [each.key, each.value]
+}`,
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "resource", LabelNames: []string{"type", "name"}, Body: &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}}},
+				},
+			},
+			want: &hclext.BodyContent{
+				Attributes: hclext.Attributes{},
+				Blocks: hclext.Blocks{
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body:   &hclext.BodyContent{Attributes: hclext.Attributes{"value": {Name: "value", Expr: hcl.StaticExpr(cty.TupleVal([]cty.Value{cty.StringVal("foo"), cty.True}), hcl.Range{})}}, Blocks: hclext.Blocks{}},
+					},
+				},
+			},
+		},
+		{
+			name: "for_each is a for expression with unknown keys",
+			config: `
+resource "aws_instance" "main" {
+  for_each = { for k in ["foo", module.meta.key] : k => "${k}-value" }
+  value    = [each.key, each.value]
+}`,
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "resource", LabelNames: []string{"type", "name"}, Body: &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}}},
+				},
+			},
+			want: &hclext.BodyContent{
+				Attributes: hclext.Attributes{},
+				Blocks: hclext.Blocks{
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body:   &hclext.BodyContent{Attributes: hclext.Attributes{"value": {Name: "value", Expr: hcl.StaticExpr(cty.TupleVal([]cty.Value{cty.StringVal("foo"), cty.StringVal("foo-value")}), hcl.Range{})}}, Blocks: hclext.Blocks{}},
+					},
+				},
+			},
+		},
+		{
+			name: "for_each is a set with unknown elements",
+			config: `
+resource "aws_instance" "main" {
+  for_each = toset(["foo", module.meta.key])
+  value    = each.key
+}`,
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "resource", LabelNames: []string{"type", "name"}, Body: &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}}},
+				},
+			},
+			want: &hclext.BodyContent{
+				Attributes: hclext.Attributes{},
+				Blocks: hclext.Blocks{
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body:   &hclext.BodyContent{Attributes: hclext.Attributes{"value": {Name: "value", Expr: hcl.StaticExpr(cty.StringVal("foo"), hcl.Range{})}}, Blocks: hclext.Blocks{}},
+					},
+				},
+			},
+		},
 		{
 			name: "for_each is empty",
 			config: `
@@ -1619,6 +1738,47 @@ resource "aws_instance" "main" {
       value = "${ebs_block_device.key}-${ebs_block_device.value}"
     }
   }
+}`,
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type:       "resource",
+						LabelNames: []string{"type", "name"},
+						Body: &hclext.BodySchema{
+							Blocks: []hclext.BlockSchema{
+								{
+									Type: "ebs_block_device",
+									Body: &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &hclext.BodyContent{
+				Attributes: hclext.Attributes{},
+				Blocks: hclext.Blocks{
+					{
+						Type:   "resource",
+						Labels: []string{"aws_instance", "main"},
+						Body: &hclext.BodyContent{
+							Attributes: hclext.Attributes{},
+							Blocks:     hclext.Blocks{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "dynamic blocks with a for expression with unknown conditions are not partially expanded",
+			config: `
+resource "aws_instance" "main" {
+  dynamic "ebs_block_device" {
+    for_each = { for k, v in { foo = true, bar = module.meta.enabled } : k => v if v }
+    content {
+      value = ebs_block_device.key
+    }
+  }
 }`,
 			schema: &hclext.BodySchema{
 				Blocks: []hclext.BlockSchema{
@@ -2213,3 +2373,559 @@ resource "aws_instance" "main" {
 		})
 	}
 }
+
+func TestEvaluateExpr_moduleOutputs(t *testing.T) {
+	expr := func(in string) hcl.Expression {
+		expr, diags := hclsyntax.ParseExpression([]byte(in), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		return expr
+	}
+
+	child := `
+variable "name" {
+  default = "default"
+}
+
+resource "aws_instance" "main" {}
+
+output "name" {
+  value = var.name
+}
+
+output "secret" {
+  value     = "secret"
+  sensitive = true
+}
+
+output "instance_id" {
+  value = aws_instance.main.id
+}
+
+variable "port" {
+  type    = number
+  default = 80
+}
+
+output "port" {
+  value = var.port
+}
+
+variable "settings" {
+  type = object({
+    name    = string
+    enabled = optional(bool, true)
+  })
+  default = null
+}
+
+output "settings" {
+  value = var.settings
+}`
+
+	tests := []struct {
+		name     string
+		config   string
+		files    map[string]string
+		expr     hcl.Expression
+		want     string
+		memoized []string
+	}{
+		{
+			name: "output",
+			config: `
+module "network" {
+  source = "./modules/network"
+  name   = "foo"
+}`,
+			expr: expr(`module.network.name`),
+			want: `cty.StringVal("foo")`,
+		},
+		{
+			name: "variable default",
+			config: `
+module "network" {
+  source = "./modules/network"
+}`,
+			expr: expr(`module.network.name`),
+			want: `cty.StringVal("default")`,
+		},
+		{
+			name: "argument referring to variables in the caller",
+			config: `
+variable "name" {
+  default = "from_root"
+}
+
+module "network" {
+  source = "./modules/network"
+  name   = var.name
+}`,
+			expr: expr(`module.network.name`),
+			want: `cty.StringVal("from_root")`,
+		},
+		{
+			name: "argument converted to the variable type",
+			config: `
+module "network" {
+  source = "./modules/network"
+  port   = "8080"
+}`,
+			expr: expr(`module.network.port`),
+			want: `cty.NumberIntVal(8080)`,
+		},
+		{
+			name: "argument with optional attribute defaults",
+			config: `
+module "network" {
+  source   = "./modules/network"
+  settings = { name = "foo" }
+}`,
+			expr: expr(`module.network.settings`),
+			want: `cty.ObjectVal(map[string]cty.Value{"enabled":cty.True, "name":cty.StringVal("foo")})`,
+		},
+		{
+			name: "argument not convertible to the variable type",
+			config: `
+module "network" {
+  source = "./modules/network"
+  port   = "http"
+}`,
+			expr: expr(`module.network.port`),
+			want: `cty.UnknownVal(cty.Number)`,
+		},
+		{
+			name: "count",
+			config: `
+module "network" {
+  count  = 2
+  source = "./modules/network"
+  name   = "foo-${count.index}"
+}`,
+			expr:     expr(`module.network[1].name`),
+			want:     `cty.StringVal("foo-1")`,
+			memoized: []string{"module.network[0]", "module.network[1]"},
+		},
+		{
+			name: "for_each",
+			config: `
+module "network" {
+  for_each = toset(["a", "b"])
+  source   = "./modules/network"
+  name     = "foo-${each.key}"
+}`,
+			expr: expr(`module.network["a"].name`),
+			want: `cty.StringVal("foo-a")`,
+		},
+		{
+			name: "unknown count",
+			config: `
+variable "instances" {
+  type = number
+}
+
+module "network" {
+  count  = var.instances
+  source = "./modules/network"
+}`,
+			expr: expr(`module.network[0].name`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "sensitive output",
+			config: `
+module "network" {
+  source = "./modules/network"
+}`,
+			expr: expr(`module.network.secret`),
+			want: `cty.StringVal("secret").Mark(marks.Sensitive)`,
+		},
+		{
+			name: "output referring to resources",
+			config: `
+module "network" {
+  source = "./modules/network"
+}`,
+			expr: expr(`module.network.instance_id`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "module is not loaded",
+			config: `
+module "network" {
+  source = "terraform-aws-modules/vpc/aws"
+}`,
+			expr: expr(`module.network.name`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name:   "undeclared module",
+			config: ``,
+			expr:   expr(`module.network.name`),
+			want:   `cty.DynamicVal`,
+		},
+		{
+			name: "nested module",
+			config: `
+module "wrapper" {
+  source = "./modules/wrapper"
+}`,
+			files: map[string]string{
+				"modules/wrapper/main.tf": `
+module "network" {
+  source = "../network"
+  name   = "nested"
+}
+
+output "name" {
+  value = module.network.name
+}`,
+			},
+			expr:     expr(`module.wrapper.name`),
+			want:     `cty.StringVal("nested")`,
+			memoized: []string{"module.wrapper", "module.wrapper.module.network"},
+		},
+		{
+			name: "modules referring to each other",
+			config: `
+module "network" {
+  source = "./modules/network"
+  name   = module.other.name
+}
+
+module "other" {
+  source = "./modules/network"
+  name   = module.network.name
+}`,
+			expr:     expr(`module.network.name`),
+			want:     `cty.DynamicVal`,
+			memoized: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			files := map[string]string{
+				"main.tf":                 test.config,
+				"modules/network/main.tf": child,
+			}
+			for name, content := range test.files {
+				files[name] = content
+			}
+			for name, content := range files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			parser := NewParser(fs)
+			mod, diags := parser.LoadConfigDir(".", ".")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			config, diags := BuildConfig(mod, ModuleWalkerFunc(func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+				source, ok := req.SourceAddr.(addrs.ModuleSourceLocal)
+				if !ok {
+					return nil, nil, nil
+				}
+				mod, diags := parser.LoadConfigDir(".", filepath.ToSlash(filepath.Join(req.Parent.Module.SourceDir, source.String())))
+				return mod, nil, diags
+			}))
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			variableValues, diags := VariableValues(config)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			evaluator := &Evaluator{
+				Meta:           &ContextMeta{Env: Workspace()},
+				ModulePath:     config.Path.UnkeyedInstanceShim(),
+				Config:         config,
+				VariableValues: variableValues,
+			}
+
+			scope := evaluator.scope()
+			got, diags := scope.EvalExpr(test.expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if test.want != got.GoString() {
+				t.Errorf("want: %s, got: %s", test.want, got.GoString())
+			}
+
+			if test.memoized != nil {
+				memoized := slices.Sorted(maps.Keys(scope.Data.(*evaluationData).ModuleCalls.values))
+				if diff := cmp.Diff(test.memoized, memoized, cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("memoized: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateExpr_resources(t *testing.T) {
+	expr := func(in string) hcl.Expression {
+		expr, diags := hclsyntax.ParseExpression([]byte(in), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		return expr
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		override string
+		expr     hcl.Expression
+		want     string
+	}{
+		{
+			name: "configured attribute",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs.bucket`),
+			want: `cty.StringVal("logs")`,
+		},
+		{
+			name: "attribute referring to variables",
+			config: `
+variable "prefix" {
+  default = "app"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.prefix}-logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs.bucket`),
+			want: `cty.StringVal("app-logs")`,
+		},
+		{
+			name: "attribute referring to other resources",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_policy" "logs" {
+  bucket = aws_s3_bucket.logs.bucket
+}`,
+			expr: expr(`aws_s3_bucket_policy.logs.bucket`),
+			want: `cty.StringVal("logs")`,
+		},
+		{
+			name: "computed attribute",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs.arn`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "multiple attributes",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			expr: expr(`[aws_s3_bucket.logs.bucket, aws_s3_bucket.logs.arn]`),
+			want: `cty.TupleVal([]cty.Value{cty.StringVal("logs"), cty.DynamicVal})`,
+		},
+		{
+			name: "nested block",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  versioning {
+    enabled = true
+  }
+}`,
+			expr: expr(`aws_s3_bucket.logs.versioning`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "meta-argument",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  provider = aws.west
+}`,
+			expr: expr(`aws_s3_bucket.logs.provider`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "whole resource",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name:   "undeclared resource",
+			config: ``,
+			expr:   expr(`aws_s3_bucket.logs.bucket`),
+			want:   `cty.DynamicVal`,
+		},
+		{
+			name: "count",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  count  = 2
+  bucket = "logs-${count.index}"
+}`,
+			expr: expr(`aws_s3_bucket.logs[1].bucket`),
+			want: `cty.StringVal("logs-1")`,
+		},
+		{
+			name: "for_each",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  for_each = { a = "foo", b = "bar" }
+  bucket   = "logs-${each.value}"
+}`,
+			expr: expr(`aws_s3_bucket.logs["b"].bucket`),
+			want: `cty.StringVal("logs-bar")`,
+		},
+		{
+			name: "splat",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  count  = 2
+  bucket = "logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs[*].bucket`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "unknown count",
+			config: `
+variable "instances" {
+  type = number
+}
+
+resource "aws_s3_bucket" "logs" {
+  count  = var.instances
+  bucket = "logs"
+}`,
+			expr: expr(`aws_s3_bucket.logs[0].bucket`),
+			want: `cty.DynamicVal`,
+		},
+		{
+			name: "data source",
+			config: `
+data "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			expr: expr(`data.aws_s3_bucket.logs.bucket`),
+			want: `cty.StringVal("logs")`,
+		},
+		{
+			name: "ephemeral resource",
+			config: `
+ephemeral "random_password" "db" {
+  length = 16
+}`,
+			expr: expr(`ephemeral.random_password.db.length`),
+			want: `cty.NumberIntVal(16).Mark(marks.Sensitive)`,
+		},
+		{
+			name: "computed attribute of ephemeral resource",
+			config: `
+ephemeral "random_password" "db" {
+  length = 16
+}`,
+			expr: expr(`ephemeral.random_password.db.result`),
+			want: `cty.DynamicVal.Mark(marks.Sensitive)`,
+		},
+		{
+			name: "sensitive",
+			config: `
+variable "bucket" {
+  sensitive = true
+  default   = "logs"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = var.bucket
+}`,
+			expr: expr(`aws_s3_bucket.logs.bucket`),
+			want: `cty.StringVal("logs").Mark(marks.Sensitive)`,
+		},
+		{
+			name: "override",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			override: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "override"
+}`,
+			expr: expr(`aws_s3_bucket.logs.bucket`),
+			want: `cty.StringVal("override")`,
+		},
+		{
+			name: "circular reference",
+			config: `
+resource "aws_s3_bucket" "foo" {
+  bucket = aws_s3_bucket.bar.bucket
+}
+
+resource "aws_s3_bucket" "bar" {
+  bucket = aws_s3_bucket.foo.bucket
+}`,
+			expr: expr(`aws_s3_bucket.foo.bucket`),
+			want: `cty.DynamicVal`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			if err := fs.WriteFile("main.tf", []byte(test.config), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			if test.override != "" {
+				if err := fs.WriteFile("main_override.tf", []byte(test.override), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			parser := NewParser(fs)
+			mod, diags := parser.LoadConfigDir(".", ".")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			config, diags := BuildConfig(mod, ModuleWalkerFunc(func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) { return nil, nil, nil }))
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			variableValues, diags := VariableValues(config)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			evaluator := &Evaluator{
+				Meta:           &ContextMeta{Env: Workspace()},
+				ModulePath:     config.Path.UnkeyedInstanceShim(),
+				Config:         config,
+				VariableValues: variableValues,
+			}
+
+			got, diags := evaluator.EvaluateExpr(test.expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if test.want != got.GoString() {
+				t.Errorf("want: %s, got: %s", test.want, got.GoString())
+			}
+		})
+	}
+}
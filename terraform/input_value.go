@@ -7,14 +7,58 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 type InputValue struct {
-	Value cty.Value
+	Value      cty.Value
+	SourceType ValueSourceType
+
+	// SourceRange is the location where the value is defined, such as an attribute
+	// in a tfvars file. For values passed via CLI flags or environment variables,
+	// this is a synthetic range like "<value for var.foo>".
+	// This is a zero value for values not supplied externally (e.g. default values).
+	SourceRange hcl.Range
+}
+
+// ValueSourceType describes what broad category of source location provided
+// a particular value.
+type ValueSourceType rune
+
+const (
+	// ValueFromUnknown is the zero value of ValueSourceType and is not valid.
+	ValueFromUnknown ValueSourceType = 0
+
+	// ValueFromAutoFile indicates that a value came from a "values file", like
+	// a .tfvars file, that was implicitly loaded by naming convention.
+	ValueFromAutoFile ValueSourceType = 'F'
+
+	// ValueFromNamedFile indicates that a value came from a named "values file",
+	// like a .tfvars file, that was passed explicitly on the command line (e.g.
+	// -var-file=foo.tfvars).
+	ValueFromNamedFile ValueSourceType = 'N'
+
+	// ValueFromCLIArg indicates that the value was provided directly in
+	// a CLI argument (e.g. --var=foo=bar).
+	ValueFromCLIArg ValueSourceType = 'A'
+
+	// ValueFromEnvVar indicates that the value was provided via an environment
+	// variable (TF_VAR_*).
+	ValueFromEnvVar ValueSourceType = 'E'
+
+	// ValueFromTestFile indicates that the value was set in a "variables" block
+	// in a test file (.tftest.hcl).
+	ValueFromTestFile ValueSourceType = 'T'
+)
+
+// IsFile returns true if the value came from a values file.
+func (s ValueSourceType) IsFile() bool {
+	return s == ValueFromAutoFile || s == ValueFromNamedFile
 }
 
 type InputValues map[string]*InputValue
@@ -82,7 +126,9 @@ func EnvironmentVariableValues(declVars map[string]*Variable) (InputValues, hcl.
 			}
 
 			envVariables[varName] = &InputValue{
-				Value: val,
+				Value:       val,
+				SourceType:  ValueFromEnvVar,
+				SourceRange: hcl.Range{Filename: fmt.Sprintf("<value for var.%s>", varName), Start: hcl.InitialPos, End: hcl.InitialPos},
 			}
 		}
 	}
@@ -132,13 +178,87 @@ func ParseVariableValues(vars []string, declVars map[string]*Variable) (InputVal
 		}
 
 		variables[name] = &InputValue{
-			Value: val,
+			Value:       val,
+			SourceType:  ValueFromCLIArg,
+			SourceRange: hcl.Range{Filename: fmt.Sprintf("<value for var.%s>", name), Start: hcl.InitialPos, End: hcl.InitialPos},
 		}
 	}
 
 	return variables, diags
 }
 
+// CheckUndeclaredValues returns warnings for the given values assigned to undeclared
+// variables. Unlike Terraform, it suggests a similar variable name if one exists,
+// as this is often caused by a typo.
+func CheckUndeclaredValues(values InputValues, declVars map[string]*Variable) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	suggestions := make([]string, 0, len(declVars))
+	for name := range declVars {
+		suggestions = append(suggestions, name)
+	}
+	sort.Strings(suggestions)
+
+	for _, name := range sortedInputValueNames(values) {
+		if _, declared := declVars[name]; declared {
+			continue
+		}
+
+		detail := fmt.Sprintf("A variable named %q was assigned, but the root module does not declare a variable of that name.", name)
+		if suggestion := nameSuggestion(name, suggestions); suggestion != "" {
+			detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Value for undeclared variable",
+			Detail:   detail,
+			Subject:  values[name].SourceRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// CheckValueTypes returns errors for the given values that cannot be converted
+// to the type constraints of the declared variables. Undeclared variables are ignored.
+func CheckValueTypes(values InputValues, declVars map[string]*Variable) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, name := range sortedInputValueNames(values) {
+		config, declared := declVars[name]
+		if !declared {
+			continue
+		}
+		val := values[name].Value
+		if val.IsNull() {
+			continue
+		}
+
+		if config.TypeDefaults != nil {
+			val = config.TypeDefaults.Apply(val)
+		}
+		if _, err := convert.Convert(val, config.ConstraintType); err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for input variable",
+				Detail:   fmt.Sprintf("The given value is not suitable for var.%s declared at %s: %s.", name, config.DeclRange, err),
+				Subject:  values[name].SourceRange.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+func sortedInputValueNames(values InputValues) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // VariableValues returns a value map based on configuration, environment variables,
 // and external input values. External input values take precedence over configuration defaults,
 // environment variables, and the last one passed takes precedence.
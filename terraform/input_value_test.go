@@ -64,16 +64,24 @@ func TestEnvironmentVariableValues(t *testing.T) {
 			},
 			want: InputValues{
 				"instance_type": &InputValue{
-					Value: cty.StringVal("t2.micro"),
+					Value:       cty.StringVal("t2.micro"),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.instance_type>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"count": &InputValue{
-					Value: cty.StringVal("5"),
+					Value:       cty.StringVal("5"),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.count>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"list": &InputValue{
-					Value: cty.StringVal("[\"foo\"]"),
+					Value:       cty.StringVal("[\"foo\"]"),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.list>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"map": &InputValue{
-					Value: cty.StringVal("{foo=\"bar\"}"),
+					Value:       cty.StringVal("{foo=\"bar\"}"),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.map>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 			},
 			errCheck: neverHappend,
@@ -94,16 +102,24 @@ func TestEnvironmentVariableValues(t *testing.T) {
 			},
 			want: InputValues{
 				"instance_type": &InputValue{
-					Value: cty.StringVal("t2.micro"),
+					Value:       cty.StringVal("t2.micro"),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.instance_type>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"count": &InputValue{
-					Value: cty.NumberIntVal(5),
+					Value:       cty.NumberIntVal(5),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.count>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"list": &InputValue{
-					Value: cty.TupleVal([]cty.Value{cty.StringVal("foo")}),
+					Value:       cty.TupleVal([]cty.Value{cty.StringVal("foo")}),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.list>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"map": &InputValue{
-					Value: cty.ObjectVal(map[string]cty.Value{"foo": cty.StringVal("bar")}),
+					Value:       cty.ObjectVal(map[string]cty.Value{"foo": cty.StringVal("bar")}),
+					SourceType:  ValueFromEnvVar,
+					SourceRange: hcl.Range{Filename: "<value for var.map>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 			},
 			errCheck: neverHappend,
@@ -192,13 +208,19 @@ func TestParseVariableValues(t *testing.T) {
 			},
 			want: InputValues{
 				"foo": &InputValue{
-					Value: cty.StringVal("bar"),
+					Value:       cty.StringVal("bar"),
+					SourceType:  ValueFromCLIArg,
+					SourceRange: hcl.Range{Filename: "<value for var.foo>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"bar": &InputValue{
-					Value: cty.TupleVal([]cty.Value{cty.StringVal("foo")}),
+					Value:       cty.TupleVal([]cty.Value{cty.StringVal("foo")}),
+					SourceType:  ValueFromCLIArg,
+					SourceRange: hcl.Range{Filename: "<value for var.bar>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 				"baz": &InputValue{
-					Value: cty.ObjectVal(map[string]cty.Value{"foo": cty.StringVal("bar")}),
+					Value:       cty.ObjectVal(map[string]cty.Value{"foo": cty.StringVal("bar")}),
+					SourceType:  ValueFromCLIArg,
+					SourceRange: hcl.Range{Filename: "<value for var.baz>", Start: hcl.InitialPos, End: hcl.InitialPos},
 				},
 			},
 			errCheck: neverHappend,
@@ -319,3 +341,120 @@ func TestVariableValues(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUndeclaredValues(t *testing.T) {
+	declared := map[string]*Variable{
+		"instance_type": {Name: "instance_type"},
+		"ami":           {Name: "ami"},
+	}
+	rng := hcl.Range{Filename: "prod.tfvars", Start: hcl.InitialPos, End: hcl.InitialPos}
+
+	tests := []struct {
+		name   string
+		values InputValues
+		want   []string
+	}{
+		{
+			name: "declared",
+			values: InputValues{
+				"instance_type": {Value: cty.StringVal("t2.micro"), SourceRange: rng},
+			},
+			want: []string{},
+		},
+		{
+			name: "undeclared with suggestion",
+			values: InputValues{
+				"instance_typ": {Value: cty.StringVal("t2.micro"), SourceRange: rng},
+			},
+			want: []string{`prod.tfvars:1,1-1: Value for undeclared variable; A variable named "instance_typ" was assigned, but the root module does not declare a variable of that name. Did you mean "instance_type"?`},
+		},
+		{
+			name: "undeclared without suggestion",
+			values: InputValues{
+				"foo": {Value: cty.StringVal("bar"), SourceRange: rng},
+				"ami": {Value: cty.StringVal("ami-1234"), SourceRange: rng},
+			},
+			want: []string{`prod.tfvars:1,1-1: Value for undeclared variable; A variable named "foo" was assigned, but the root module does not declare a variable of that name.`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := CheckUndeclaredValues(test.values, declared)
+
+			got := []string{}
+			for _, diag := range diags {
+				if diag.Severity != hcl.DiagWarning {
+					t.Errorf("expected a warning, but got %v", diag.Severity)
+				}
+				got = append(got, hcl.Diagnostics{diag}.Error())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCheckValueTypes(t *testing.T) {
+	declared := map[string]*Variable{
+		"count": {Name: "count", ConstraintType: cty.Number, DeclRange: hcl.Range{Filename: "variables.tf", Start: hcl.InitialPos, End: hcl.InitialPos}},
+		"tags":  {Name: "tags", ConstraintType: cty.Map(cty.String), DeclRange: hcl.Range{Filename: "variables.tf", Start: hcl.InitialPos, End: hcl.InitialPos}},
+	}
+	rng := hcl.Range{Filename: "prod.tfvars", Start: hcl.InitialPos, End: hcl.InitialPos}
+
+	tests := []struct {
+		name   string
+		values InputValues
+		want   []string
+	}{
+		{
+			name: "convertible",
+			values: InputValues{
+				"count": {Value: cty.StringVal("1"), SourceRange: rng},
+				"tags":  {Value: cty.ObjectVal(map[string]cty.Value{"Name": cty.StringVal("web")}), SourceRange: rng},
+			},
+			want: []string{},
+		},
+		{
+			name: "null and unknown",
+			values: InputValues{
+				"count": {Value: cty.NullVal(cty.String), SourceRange: rng},
+				"tags":  {Value: cty.DynamicVal, SourceRange: rng},
+			},
+			want: []string{},
+		},
+		{
+			name: "not convertible",
+			values: InputValues{
+				"count": {Value: cty.StringVal("one"), SourceRange: rng},
+				"tags":  {Value: cty.StringVal("web"), SourceRange: rng},
+			},
+			want: []string{
+				`prod.tfvars:1,1-1: Invalid value for input variable; The given value is not suitable for var.count declared at variables.tf:1,1-1: a number is required.`,
+				`prod.tfvars:1,1-1: Invalid value for input variable; The given value is not suitable for var.tags declared at variables.tf:1,1-1: map of string required.`,
+			},
+		},
+		{
+			name: "undeclared",
+			values: InputValues{
+				"foo": {Value: cty.StringVal("bar"), SourceRange: rng},
+			},
+			want: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := CheckValueTypes(test.values, declared)
+
+			got := []string{}
+			for _, diag := range diags {
+				got = append(got, hcl.Diagnostics{diag}.Error())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -19,10 +19,17 @@ import (
 // place of the requested object so that type checking can still proceed. In
 // cases where it's not possible to even determine a suitable result type,
 // cty.DynamicVal is returned along with errors describing the problem.
+//
+// Unlike Terraform, GetResource receives the names of the referenced attributes,
+// because resource schemas are not available and only the attributes literally
+// set in the configuration can be resolved. If the whole resource is referenced,
+// the attribute names are nil.
 type Data interface {
 	GetCountAttr(addrs.CountAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetForEachAttr(addrs.ForEachAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetLocalValue(addrs.LocalValue, hcl.Range) (cty.Value, hcl.Diagnostics)
+	GetModule(addrs.ModuleCall, hcl.Range) (cty.Value, hcl.Diagnostics)
+	GetResource(addrs.Resource, []string, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetPathAttr(addrs.PathAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetTerraformAttr(addrs.TerraformAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetInputVariable(addrs.InputVariable, hcl.Range) (cty.Value, hcl.Diagnostics)
@@ -10,6 +10,8 @@ type dataForTests struct {
 	CountAttrs     map[string]cty.Value
 	ForEachAttrs   map[string]cty.Value
 	LocalValues    map[string]cty.Value
+	Modules        map[string]cty.Value
+	Resources      map[string]cty.Value
 	PathAttrs      map[string]cty.Value
 	TerraformAttrs map[string]cty.Value
 	InputVariables map[string]cty.Value
@@ -33,6 +35,17 @@ func (d *dataForTests) GetLocalValue(addr addrs.LocalValue, rng hcl.Range) (cty.
 	return d.LocalValues[addr.Name], nil
 }
 
+func (d *dataForTests) GetModule(addr addrs.ModuleCall, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	return d.Modules[addr.String()], nil
+}
+
+func (d *dataForTests) GetResource(addr addrs.Resource, attrs []string, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	if attrs == nil {
+		return cty.DynamicVal, nil
+	}
+	return d.Resources[addr.String()], nil
+}
+
 func (d *dataForTests) GetPathAttr(addr addrs.PathAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	return d.PathAttrs[addr.Name], nil
 }
@@ -2,6 +2,7 @@ package lang
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
@@ -132,9 +133,14 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 	// it, since that allows us to gather a full set of any errors and
 	// warnings, but once we've gathered all the data we'll then skip anything
 	// that's redundant in the process of populating our values map.
-	managedResources := map[string]cty.Value{}
+	managedResources := map[string]map[string]cty.Value{}
+	dataResources := map[string]map[string]cty.Value{}
+	ephemeralResources := map[string]map[string]cty.Value{}
+	resourceRefs := []*resourceRef{}
+	resourceRefsByAddr := map[string]*resourceRef{}
 	inputVariables := map[string]cty.Value{}
 	localValues := map[string]cty.Value{}
+	wholeModules := map[string]cty.Value{}
 	pathAttrs := map[string]cty.Value{}
 	terraformAttrs := map[string]cty.Value{}
 	countAttrs := map[string]cty.Value{}
@@ -151,16 +157,28 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 		switch addr := rawSubj.(type) {
 		case addrs.ResourceInstance:
 			rawSubj = addr.ContainingResource()
+		case addrs.ModuleCallInstance:
+			rawSubj = addr.Call
+		case addrs.ModuleCallInstanceOutput:
+			rawSubj = addr.Call.Call
 		}
 
 		switch subj := rawSubj.(type) {
 		case addrs.Resource:
-			// Managed resources are not supported by TFLint, but it does support arbitrary
-			// key names, so it gathers the referenced resource names.
-			if subj.Mode != addrs.ManagedResourceMode {
-				continue
+			// Resource values are resolved after all references are gathered,
+			// because only the referenced attributes are resolved.
+			key := subj.String()
+			r, exists := resourceRefsByAddr[key]
+			if !exists {
+				r = &resourceRef{Addr: subj, SourceRange: rng, Attrs: map[string]bool{}}
+				resourceRefsByAddr[key] = r
+				resourceRefs = append(resourceRefs, r)
+			}
+			if name, ok := referencedAttrName(ref.Remaining); ok {
+				r.Attrs[name] = true
+			} else {
+				r.Whole = true
 			}
-			managedResources[subj.Type] = cty.UnknownVal(cty.DynamicPseudoType)
 
 		case addrs.InputVariable:
 			val, valDiags := normalizeRefValue(s.Data.GetInputVariable(subj, rng))
@@ -172,6 +190,11 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 			diags = diags.Extend(valDiags)
 			localValues[subj.Name] = val
 
+		case addrs.ModuleCall:
+			val, valDiags := normalizeRefValue(s.Data.GetModule(subj, rng))
+			diags = diags.Extend(valDiags)
+			wholeModules[subj.Name] = val
+
 		case addrs.PathAttr:
 			val, valDiags := normalizeRefValue(s.Data.GetPathAttr(subj, rng))
 			diags = diags.Extend(valDiags)
@@ -194,24 +217,61 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 		}
 	}
 
+	for _, r := range resourceRefs {
+		val, valDiags := normalizeRefValue(s.Data.GetResource(r.Addr, r.AttrNames(), r.SourceRange))
+		diags = diags.Extend(valDiags)
+
+		resources := managedResources
+		switch r.Addr.Mode {
+		case addrs.DataResourceMode:
+			resources = dataResources
+		case addrs.EphemeralResourceMode:
+			resources = ephemeralResources
+		}
+		if _, exists := resources[r.Addr.Type]; !exists {
+			resources[r.Addr.Type] = map[string]cty.Value{}
+		}
+		resources[r.Addr.Type][r.Addr.Name] = val
+	}
+
 	// Managed resources are exposed in two different locations. This is
 	// at the top level where the resource type name is the root of the
 	// traversal.
 	for k, v := range managedResources {
-		vals[k] = v
+		vals[k] = cty.ObjectVal(v)
 	}
 
 	vals["var"] = cty.ObjectVal(inputVariables)
 	vals["local"] = cty.ObjectVal(localValues)
+	vals["module"] = cty.ObjectVal(wholeModules)
 	vals["path"] = cty.ObjectVal(pathAttrs)
 	vals["terraform"] = cty.ObjectVal(terraformAttrs)
 	vals["count"] = cty.ObjectVal(countAttrs)
 	vals["each"] = cty.ObjectVal(forEachAttrs)
 
+	// Data sources and ephemeral resources are unknown if not referenced,
+	// as they were before static evaluation of resources is supported.
+	if len(dataResources) > 0 {
+		dataVals := map[string]cty.Value{}
+		for k, v := range dataResources {
+			dataVals[k] = cty.ObjectVal(v)
+		}
+		vals["data"] = cty.ObjectVal(dataVals)
+	} else {
+		vals["data"] = cty.UnknownVal(cty.DynamicPseudoType)
+	}
+	if len(ephemeralResources) > 0 {
+		ephemeralVals := map[string]cty.Value{}
+		for k, v := range ephemeralResources {
+			ephemeralVals[k] = cty.ObjectVal(v)
+		}
+		vals["ephemeral"] = cty.ObjectVal(ephemeralVals)
+	} else {
+		vals["ephemeral"] = cty.UnknownVal(cty.DynamicPseudoType)
+	}
+
 	// The following are unknown values as they are not supported by TFLint.
 	vals["resource"] = cty.UnknownVal(cty.DynamicPseudoType)
-	vals["data"] = cty.UnknownVal(cty.DynamicPseudoType)
-	vals["module"] = cty.UnknownVal(cty.DynamicPseudoType)
 	vals["self"] = cty.UnknownVal(cty.DynamicPseudoType)
 
 	return ctx, diags
@@ -227,3 +287,41 @@ func normalizeRefValue(val cty.Value, diags hcl.Diagnostics) (cty.Value, hcl.Dia
 	}
 	return val, diags
 }
+
+// resourceRef is a set of references to a resource in an expression.
+type resourceRef struct {
+	Addr        addrs.Resource
+	SourceRange hcl.Range
+
+	// Attrs is the set of attribute names referenced, like "foo" in "aws_instance.main.foo".
+	Attrs map[string]bool
+	// Whole is true if the resource is referenced without attributes, like "aws_instance.main".
+	Whole bool
+}
+
+// AttrNames returns the sorted referenced attribute names.
+// If the whole resource is referenced, it returns nil.
+func (r *resourceRef) AttrNames() []string {
+	if r.Whole {
+		return nil
+	}
+	names := make([]string, 0, len(r.Attrs))
+	for name := range r.Attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// referencedAttrName returns the name of the first attribute in the remaining traversal.
+// It returns false if the traversal does not start with an attribute access.
+func referencedAttrName(remaining hcl.Traversal) (string, bool) {
+	if len(remaining) == 0 {
+		return "", false
+	}
+	attr, ok := remaining[0].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
@@ -33,6 +33,28 @@ func TestScopeEvalContext(t *testing.T) {
 		InputVariables: map[string]cty.Value{
 			"baz": cty.StringVal("boop"),
 		},
+		Modules: map[string]cty.Value{
+			"module.foo": cty.ObjectVal(map[string]cty.Value{
+				"output0": cty.StringVal("bar0"),
+				"output1": cty.StringVal("bar1"),
+			}),
+		},
+		Resources: map[string]cty.Value{
+			"null_resource.foo": cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal("bar"),
+			}),
+			"null_resource.each": cty.ObjectVal(map[string]cty.Value{
+				"each1": cty.ObjectVal(map[string]cty.Value{
+					"attr": cty.StringVal("each1"),
+				}),
+			}),
+			"data.null_data_source.foo": cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal("baz"),
+			}),
+			"ephemeral.random_password.foo": cty.ObjectVal(map[string]cty.Value{
+				"result": cty.StringVal("secret"),
+			}),
+		},
 	}
 
 	tests := []struct {
@@ -49,10 +71,10 @@ func TestScopeEvalContext(t *testing.T) {
 				"count": cty.ObjectVal(map[string]cty.Value{
 					"index": cty.NumberIntVal(0),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
@@ -61,10 +83,10 @@ func TestScopeEvalContext(t *testing.T) {
 				"each": cty.ObjectVal(map[string]cty.Value{
 					"key": cty.StringVal("a"),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
@@ -73,10 +95,10 @@ func TestScopeEvalContext(t *testing.T) {
 				"each": cty.ObjectVal(map[string]cty.Value{
 					"value": cty.NumberIntVal(1),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
@@ -85,104 +107,184 @@ func TestScopeEvalContext(t *testing.T) {
 				"local": cty.ObjectVal(map[string]cty.Value{
 					"foo": cty.StringVal("bar"),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.foo`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"foo": cty.DynamicVal,
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.foo.attr`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"foo": cty.ObjectVal(map[string]cty.Value{
+						"attr": cty.StringVal("bar"),
+					}),
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.multi`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"multi": cty.DynamicVal,
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.multi[1]`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"multi": cty.DynamicVal,
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.each["each1"]`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"each": cty.DynamicVal,
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`null_resource.each["each1"].attr`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"each": cty.ObjectVal(map[string]cty.Value{
+						"each1": cty.ObjectVal(map[string]cty.Value{
+							"attr": cty.StringVal("each1"),
+						}),
+					}),
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
 			`foo(null_resource.multi, null_resource.multi[1])`,
 			map[string]cty.Value{
-				"null_resource": cty.DynamicVal,
-				"resource":      cty.DynamicVal,
-				"data":          cty.DynamicVal,
-				"module":        cty.DynamicVal,
-				"self":          cty.DynamicVal,
+				"null_resource": cty.ObjectVal(map[string]cty.Value{
+					"multi": cty.DynamicVal,
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
-			`path.module`,
+			`data.null_data_source.foo.attr`,
 			map[string]cty.Value{
-				"path": cty.ObjectVal(map[string]cty.Value{
-					"module": cty.StringVal("foo/bar"),
+				"data": cty.ObjectVal(map[string]cty.Value{
+					"null_data_source": cty.ObjectVal(map[string]cty.Value{
+						"foo": cty.ObjectVal(map[string]cty.Value{
+							"attr": cty.StringVal("baz"),
+						}),
+					}),
+				}),
+				"ephemeral": cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"self":      cty.DynamicVal,
+			},
+		},
+		{
+			`ephemeral.random_password.foo.result`,
+			map[string]cty.Value{
+				"ephemeral": cty.ObjectVal(map[string]cty.Value{
+					"random_password": cty.ObjectVal(map[string]cty.Value{
+						"foo": cty.ObjectVal(map[string]cty.Value{
+							"result": cty.StringVal("secret"),
+						}),
+					}),
 				}),
 				"resource": cty.DynamicVal,
 				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
 				"self":     cty.DynamicVal,
 			},
 		},
+		{
+			`path.module`,
+			map[string]cty.Value{
+				"path": cty.ObjectVal(map[string]cty.Value{
+					"module": cty.StringVal("foo/bar"),
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
+			},
+		},
 		{
 			`terraform.workspace`,
 			map[string]cty.Value{
 				"terraform": cty.ObjectVal(map[string]cty.Value{
 					"workspace": cty.StringVal("default"),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
+			},
+		},
+		{
+			`module.foo`,
+			map[string]cty.Value{
+				"module": cty.ObjectVal(map[string]cty.Value{
+					"foo": cty.ObjectVal(map[string]cty.Value{
+						"output0": cty.StringVal("bar0"),
+						"output1": cty.StringVal("bar1"),
+					}),
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
+			},
+		},
+		{
+			`module.foo.output1`,
+			map[string]cty.Value{
+				"module": cty.ObjectVal(map[string]cty.Value{
+					"foo": cty.ObjectVal(map[string]cty.Value{
+						"output0": cty.StringVal("bar0"),
+						"output1": cty.StringVal("bar1"),
+					}),
+				}),
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 		{
@@ -191,10 +293,10 @@ func TestScopeEvalContext(t *testing.T) {
 				"var": cty.ObjectVal(map[string]cty.Value{
 					"baz": cty.StringVal("boop"),
 				}),
-				"resource": cty.DynamicVal,
-				"data":     cty.DynamicVal,
-				"module":   cty.DynamicVal,
-				"self":     cty.DynamicVal,
+				"resource":  cty.DynamicVal,
+				"data":      cty.DynamicVal,
+				"ephemeral": cty.DynamicVal,
+				"self":      cty.DynamicVal,
 			},
 		},
 	}
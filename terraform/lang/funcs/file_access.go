@@ -0,0 +1,124 @@
+package funcs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// ErrFileNotFound is an error returned when a file passed to a filesystem function does not exist.
+var ErrFileNotFound = errors.New("no file exists")
+
+// ErrOutsideSandbox is an error returned when a path passed to a filesystem function is
+// outside the sandbox directory.
+var ErrOutsideSandbox = errors.New("filesystem functions can only access files in the sandbox directory")
+
+// MakeFileAccessCheckedFunc wraps a filesystem function to check the path passed as
+// the first argument before accessing the file. For the fileset function, the path
+// joined with the glob pattern is also checked.
+//
+// If sandboxDir is not empty, paths outside the directory are rejected. Symbolic links
+// are resolved before checking. If mustExist is true, missing files are rejected.
+//
+// Unlike errors returned by the underlying functions, these errors are not wrapped in
+// function.ArgError, so that callers can identify them with errors.Is.
+func MakeFileAccessCheckedFunc(fn function.Function, baseDir string, sandboxDir string, mustExist bool) function.Function {
+	params := fn.Params()
+	hasPattern := len(params) > 1 && params[1].Name == "pattern"
+
+	check := func(args []cty.Value) error {
+		if !args[0].IsKnown() {
+			return nil
+		}
+		pathArg, pathMarks := args[0].Unmark()
+		path, err := homedir.Expand(pathArg.AsString())
+		if err != nil {
+			return fmt.Errorf("failed to expand ~: %w", err)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		path = filepath.Clean(path)
+
+		if sandboxDir != "" {
+			if !withinDir(sandboxDir, path) {
+				return fmt.Errorf("cannot access %s; %w", redactIfSensitive(path, pathMarks), ErrOutsideSandbox)
+			}
+			// The glob pattern can also traverse parent directories.
+			if hasPattern && args[1].IsKnown() {
+				patternArg, patternMarks := args[1].Unmark()
+				pattern := filepath.Join(path, patternArg.AsString())
+				if !withinDir(sandboxDir, pattern) {
+					return fmt.Errorf("cannot access %s; %w", redactIfSensitive(pattern, pathMarks, patternMarks), ErrOutsideSandbox)
+				}
+			}
+		}
+
+		if mustExist {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				// An extra Terraform-specific hint for this situation
+				return fmt.Errorf("%w at %s; this function works only with files that are distributed as part of the configuration source code, so if this file will be created by a resource in this configuration you must instead obtain this result from an attribute of that resource", ErrFileNotFound, redactIfSensitive(path, pathMarks))
+			}
+		}
+		return nil
+	}
+
+	return function.New(&function.Spec{
+		Description: fn.Description(),
+		Params:      params,
+		VarParam:    fn.VarParam(),
+		Type: func(args []cty.Value) (cty.Type, error) {
+			// Some functions like templatefile read the file to determine the type.
+			if err := check(args); err != nil {
+				return cty.DynamicPseudoType, err
+			}
+			return fn.ReturnTypeForValues(args)
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			if err := check(args); err != nil {
+				return cty.UnknownVal(retType), err
+			}
+			return fn.Call(args)
+		},
+	})
+}
+
+// withinDir returns true if the path is in the directory.
+func withinDir(dir string, path string) bool {
+	dir, path = resolvePath(dir), resolvePath(path)
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath returns the absolute path with symbolic links resolved.
+// If the path does not exist, the longest existing parent is resolved instead.
+func resolvePath(path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	remain := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(resolved, remain)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, remain)
+		}
+		remain = filepath.Join(filepath.Base(path), remain)
+		path = parent
+	}
+}
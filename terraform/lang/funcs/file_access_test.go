@@ -0,0 +1,138 @@
+package funcs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nholuongut/tflint-plugin-sdk/terraform/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func TestMakeFileAccessCheckedFunc(t *testing.T) {
+	dir := t.TempDir()
+	sandbox := filepath.Join(dir, "sandbox")
+	if err := os.Mkdir(sandbox, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sandbox, "hello.txt"), []byte("Hello ${name}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(sandbox, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name      string
+		Func      function.Function
+		MustExist bool
+		Args      []cty.Value
+		Want      cty.Value
+		Err       error
+	}{
+		{
+			Name:      "file in the sandbox",
+			Func:      MakeFileFunc(sandbox, false),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("hello.txt")},
+			Want:      cty.StringVal("Hello ${name}"),
+		},
+		{
+			Name:      "file outside the sandbox",
+			Func:      MakeFileFunc(sandbox, false),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("../secret.txt")},
+			Err:       ErrOutsideSandbox,
+		},
+		{
+			Name:      "sensitive path outside the sandbox",
+			Func:      MakeFileFunc(sandbox, false),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("../secret.txt").Mark(marks.Sensitive)},
+			Err:       ErrOutsideSandbox,
+		},
+		{
+			Name:      "symlink to a file outside the sandbox",
+			Func:      MakeFileFunc(sandbox, false),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("link.txt")},
+			Err:       ErrOutsideSandbox,
+		},
+		{
+			Name:      "missing file",
+			Func:      MakeFileFunc(sandbox, false),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("missing.txt")},
+			Err:       ErrFileNotFound,
+		},
+		{
+			Name:      "templatefile outside the sandbox",
+			Func:      MakeTemplateFileFunc(sandbox, nil),
+			MustExist: true,
+			Args:      []cty.Value{cty.StringVal("../secret.txt"), cty.EmptyObjectVal},
+			Err:       ErrOutsideSandbox,
+		},
+		{
+			Name: "fileexists outside the sandbox",
+			Func: MakeFileExistsFunc(sandbox),
+			Args: []cty.Value{cty.StringVal("../secret.txt")},
+			Err:  ErrOutsideSandbox,
+		},
+		{
+			Name: "fileexists with missing file",
+			Func: MakeFileExistsFunc(sandbox),
+			Args: []cty.Value{cty.StringVal("missing.txt")},
+			Want: cty.False,
+		},
+		{
+			Name: "fileset in the sandbox",
+			Func: MakeFileSetFunc(sandbox),
+			Args: []cty.Value{cty.StringVal("."), cty.StringVal("hello.*")},
+			Want: cty.SetVal([]cty.Value{cty.StringVal("hello.txt")}),
+		},
+		{
+			Name: "fileset with a pattern outside the sandbox",
+			Func: MakeFileSetFunc(sandbox),
+			Args: []cty.Value{cty.StringVal("."), cty.StringVal("../*.txt")},
+			Err:  ErrOutsideSandbox,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fn := MakeFileAccessCheckedFunc(test.Func, sandbox, sandbox, test.MustExist)
+			got, err := fn.Call(test.Args)
+
+			if test.Err != nil {
+				if !errors.Is(err, test.Err) {
+					t.Fatalf("want %s, got %v", test.Err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestMakeFileAccessCheckedFunc_sensitive(t *testing.T) {
+	fn := MakeFileAccessCheckedFunc(MakeFileFunc(".", false), ".", "", true)
+
+	_, err := fn.Call([]cty.Value{cty.StringVal("testdata/missing").Mark(marks.Sensitive)})
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Fatalf("want %s, got %v", ErrFileNotFound, err)
+	}
+	want := "no file exists at (sensitive value); this function works only with files that are distributed as part of the configuration source code, so if this file will be created by a resource in this configuration you must instead obtain this result from an attribute of that resource"
+	if err.Error() != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", err, want)
+	}
+}
@@ -1,6 +1,7 @@
 package lang
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -15,6 +16,7 @@ import (
 type FunctionCall struct {
 	Name      string
 	ArgsCount int
+	NameRange hcl.Range
 }
 
 // FunctionCallsInExpr finds all of the function calls in the given expression.
@@ -30,17 +32,54 @@ func FunctionCallsInExpr(expr hcl.Expression) ([]*FunctionCall, hcl.Diagnostics)
 	ret := []*FunctionCall{}
 
 	for _, node := range nodes {
-		visitDiags := hclsyntax.VisitAll(node, func(n hclsyntax.Node) hcl.Diagnostics {
-			if funcCallExpr, ok := n.(*hclsyntax.FunctionCallExpr); ok {
-				ret = append(ret, &FunctionCall{
-					Name:      funcCallExpr.Name,
-					ArgsCount: len(funcCallExpr.Args),
-				})
-			}
-			return nil
-		})
+		calls, visitDiags := functionCallsInNode(node)
 		diags = diags.Extend(visitDiags)
+		ret = append(ret, calls...)
+	}
+	return ret, diags
+}
+
+// FunctionCallsInBody finds all of the function calls in the given body, including nested blocks.
+// In JSON syntax, nested blocks are indistinguishable from attributes, so the body is
+// walked as attributes of object expressions.
+func FunctionCallsInBody(body hcl.Body) ([]*FunctionCall, hcl.Diagnostics) {
+	if native, ok := body.(*hclsyntax.Body); ok {
+		// Attributes are walked in map order, so sort calls by position
+		ret, diags := functionCallsInNode(native)
+		sort.SliceStable(ret, func(i, j int) bool {
+			return ret[i].NameRange.Start.Byte < ret[j].NameRange.Start.Byte
+		})
+		return ret, diags
+	}
+
+	attrs, diags := body.JustAttributes()
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	ret := []*FunctionCall{}
+	for _, name := range names {
+		calls, callDiags := FunctionCallsInExpr(attrs[name].Expr)
+		diags = diags.Extend(callDiags)
+		ret = append(ret, calls...)
+	}
+	return ret, diags
+}
+
+func functionCallsInNode(node hclsyntax.Node) ([]*FunctionCall, hcl.Diagnostics) {
+	ret := []*FunctionCall{}
+	diags := hclsyntax.VisitAll(node, func(n hclsyntax.Node) hcl.Diagnostics {
+		if funcCallExpr, ok := n.(*hclsyntax.FunctionCallExpr); ok {
+			ret = append(ret, &FunctionCall{
+				Name:      funcCallExpr.Name,
+				ArgsCount: len(funcCallExpr.Args),
+				NameRange: funcCallExpr.NameRange,
+			})
+		}
+		return nil
+	})
 	return ret, diags
 }
 
@@ -1,9 +1,11 @@
 package lang
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/json"
@@ -115,7 +117,80 @@ func TestFunctionCallsInExpr(t *testing.T) {
 				t.Fatal(diags)
 			}
 
-			if diff := cmp.Diff(test.want, got); diff != "" {
+			// Ranges are checked in TestFunctionCallsInBody
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreFields(FunctionCall{}, "NameRange")); diff != "" {
+				t.Errorf("diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionCallsInBody(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		src  string
+		want []*FunctionCall
+	}{
+		{
+			name: "native syntax",
+			file: "main.tf",
+			src: `
+resource "aws_instance" "main" {
+  ami = lower(var.ami)
+
+  ebs_block_device {
+    device_name = startswith(var.name, "/dev") ? var.name : "/dev/sda"
+  }
+}`,
+			want: []*FunctionCall{
+				{
+					Name:      "lower",
+					ArgsCount: 1,
+					NameRange: hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 3, Column: 9, Byte: 42}, End: hcl.Pos{Line: 3, Column: 14, Byte: 47}},
+				},
+				{
+					Name:      "startswith",
+					ArgsCount: 2,
+					NameRange: hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 6, Column: 19, Byte: 97}, End: hcl.Pos{Line: 6, Column: 29, Byte: 107}},
+				},
+			},
+		},
+		{
+			name: "JSON syntax",
+			file: "main.tf.json",
+			src:  `{"resource": {"aws_instance": {"main": {"ami": "${lower(var.ami)}"}}}, "output": {"name": {"value": "${upper(var.name)}"}}}`,
+			want: []*FunctionCall{
+				{Name: "upper", ArgsCount: 1},
+				{Name: "lower", ArgsCount: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var file *hcl.File
+			var diags hcl.Diagnostics
+			if strings.HasSuffix(test.file, ".json") {
+				file, diags = json.Parse([]byte(test.src), test.file)
+			} else {
+				file, diags = hclsyntax.ParseConfig([]byte(test.src), test.file, hcl.InitialPos)
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got, diags := FunctionCallsInBody(file.Body)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			opts := []cmp.Option{}
+			if strings.HasSuffix(test.file, ".json") {
+				// Ranges in JSON syntax are relative to the string literals
+				opts = append(opts, cmpopts.IgnoreFields(FunctionCall{}, "NameRange"))
+			}
+			if diff := cmp.Diff(test.want, got, opts...); diff != "" {
 				t.Errorf("diff: %s", diff)
 			}
 		})
@@ -0,0 +1,44 @@
+package lang
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// functionVersions are the Terraform versions in which functions were introduced.
+// Functions available in all Terraform versions since v1.0 are not listed.
+var functionVersions = map[string]*version.Version{
+	"endswith":       version.Must(version.NewVersion("1.3.0")),
+	"startswith":     version.Must(version.NewVersion("1.3.0")),
+	"timecmp":        version.Must(version.NewVersion("1.3.0")),
+	"plantimestamp":  version.Must(version.NewVersion("1.5.0")),
+	"strcontains":    version.Must(version.NewVersion("1.5.0")),
+	"issensitive":    version.Must(version.NewVersion("1.8.0")),
+	"templatestring": version.Must(version.NewVersion("1.9.0")),
+}
+
+// namespacedFunctionVersion is the Terraform version in which namespaced function calls,
+// such as "core::" and "provider::" functions, were introduced.
+var namespacedFunctionVersion = version.Must(version.NewVersion("1.8.0"))
+
+// FunctionIntroducedIn returns the Terraform version in which the given function was
+// introduced. Returns nil if the function is available in all versions since v1.0,
+// including unknown functions.
+func FunctionIntroducedIn(name string) *version.Version {
+	ret := functionVersions[strings.TrimPrefix(name, "core::")]
+	if strings.Contains(name, "::") && (ret == nil || ret.LessThan(namespacedFunctionVersion)) {
+		ret = namespacedFunctionVersion
+	}
+	return ret
+}
+
+// FunctionAvailable returns true if the given function is available in the given
+// Terraform version. If the version is nil, all functions are available.
+func FunctionAvailable(name string, v *version.Version) bool {
+	if v == nil {
+		return true
+	}
+	since := FunctionIntroducedIn(name)
+	return since == nil || !v.Core().LessThan(since)
+}
@@ -4,6 +4,8 @@
 package lang
 
 import (
+	"strings"
+
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
 	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
@@ -21,6 +23,15 @@ var impureFunctions = []string{
 	"uuid",
 }
 
+// DefaultDeterministicResults are the results of impure functions in deterministic mode.
+// plantimestamp is not impure, but is included because it always returns an unknown value otherwise.
+var DefaultDeterministicResults = map[string]string{
+	"bcrypt":        "$2a$10$" + strings.Repeat("0", 53),
+	"plantimestamp": "1970-01-01T00:00:00Z",
+	"timestamp":     "1970-01-01T00:00:00Z",
+	"uuid":          "00000000-0000-0000-0000-000000000000",
+}
+
 // filesystemFunctions are the functions that allow interacting with arbitrary
 // paths in the local filesystem, and which can therefore have their results
 // vary based on something other than their arguments, and might allow template
@@ -196,6 +207,20 @@ func (s *Scope) Functions() map[string]function.Function {
 		coreFuncs["templatefile"] = funcs.MakeTemplateFileFunc(s.BaseDir, funcsFunc)
 		coreFuncs["templatestring"] = funcs.MakeTemplateStringFunc(funcsFunc)
 
+		// Filesystem functions check paths before accessing files, so that missing
+		// files and files outside the sandbox can be identified by callers.
+		for _, name := range filesystemFunctions.Elems() {
+			mustExist := name != "fileexists" && name != "fileset"
+			if !mustExist && s.SandboxDir == "" {
+				continue
+			}
+			coreFuncs[name] = funcs.MakeFileAccessCheckedFunc(coreFuncs[name], s.BaseDir, s.SandboxDir, mustExist)
+		}
+
+		for name, result := range s.DeterministicResults {
+			coreFuncs[name] = newFixedResultFunction(coreFuncs[name], cty.StringVal(result))
+		}
+
 		if s.PureOnly {
 			// Force our few impure functions to return unknown so that we
 			// can defer evaluating them until a later pass.
@@ -218,12 +243,48 @@ func (s *Scope) Functions() map[string]function.Function {
 		s.funcs["provider::terraform::encode_tfvars"] = terraform.EncodeTfvarsFunc
 		s.funcs["provider::terraform::decode_tfvars"] = terraform.DecodeTfvarsFunc
 		s.funcs["provider::terraform::encode_expr"] = terraform.EncodeExprFunc
+
+		// Functions not available in the target version are reported separately,
+		// so calls to them are just evaluated as unknown values here.
+		for name, fn := range s.funcs {
+			if !FunctionAvailable(name, s.TerraformVersion) {
+				s.funcs[name] = newUnavailableFunction(fn)
+			}
+		}
 	}
 	s.funcsLock.Unlock()
 
 	return s.funcs
 }
 
+// newFixedResultFunction creates a function that always returns the given value.
+// The parameters are the same as the given function so that invalid calls are still rejected.
+func newFixedResultFunction(fn function.Function, result cty.Value) function.Function {
+	return function.New(&function.Spec{
+		Description: fn.Description(),
+		Params:      fn.Params(),
+		VarParam:    fn.VarParam(),
+		Type:        function.StaticReturnType(result.Type()),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return result, nil
+		},
+	})
+}
+
+// newUnavailableFunction creates a function that always returns an unknown value.
+// The parameters are the same as the given function so that invalid calls are still rejected.
+func newUnavailableFunction(fn function.Function) function.Function {
+	return function.New(&function.Spec{
+		Description: fn.Description(),
+		Params:      fn.Params(),
+		VarParam:    fn.VarParam(),
+		Type:        function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.DynamicVal, nil
+		},
+	})
+}
+
 // NewMockFunction creates a mock function that returns a dynamic value.
 // This is primarily used to replace provider-defined functions.
 func NewMockFunction(call *FunctionCall) function.Function {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	homedir "github.com/mitchellh/go-homedir"
@@ -1219,6 +1220,74 @@ func TestFunctions(t *testing.T) {
 	}
 }
 
+func TestFunctions_deterministic(t *testing.T) {
+	tests := []struct {
+		src  string
+		want cty.Value
+	}{
+		{
+			src:  `timestamp()`,
+			want: cty.StringVal("2024-01-01T00:00:00Z"),
+		},
+		{
+			src:  `formatdate("YYYY-MM-DD", timestamp())`,
+			want: cty.StringVal("2024-01-01"),
+		},
+		{
+			src:  `plantimestamp()`,
+			want: cty.StringVal("1970-01-01T00:00:00Z"),
+		},
+		{
+			src:  `uuid()`,
+			want: cty.StringVal("00000000-0000-0000-0000-000000000000"),
+		},
+		{
+			src:  `length(bcrypt("hello", 12))`,
+			want: cty.NumberIntVal(60),
+		},
+	}
+
+	scope := &Scope{
+		Data: &dataForTests{},
+		DeterministicResults: map[string]string{
+			"bcrypt":        DefaultDeterministicResults["bcrypt"],
+			"plantimestamp": DefaultDeterministicResults["plantimestamp"],
+			"timestamp":     "2024-01-01T00:00:00Z",
+			"uuid":          DefaultDeterministicResults["uuid"],
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(test.src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags)
+			}
+
+			got, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if !test.want.RawEquals(got) {
+				t.Errorf("wrong result\nexpr: %s\ngot:  %#v\nwant: %#v", test.src, got, test.want)
+			}
+		})
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		expr, parseDiags := hclsyntax.ParseExpression([]byte(`uuid("foo")`), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+		if parseDiags.HasErrors() {
+			t.Fatal(parseDiags)
+		}
+
+		_, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+		if !diags.HasErrors() {
+			t.Fatal("expected an error, but got nothing")
+		}
+	})
+}
+
 const (
 	CipherBase64 = "eczGaDhXDbOFRZGhjx2etVzWbRqWDlmq0bvNt284JHVbwCgObiuyX9uV0LSAMY707IEgMkExJqXmsB4OWKxvB7epRB9G/3+F+pcrQpODlDuL9oDUAsa65zEpYF0Wbn7Oh7nrMQncyUPpyr9WUlALl0gRWytOA23S+y5joa4M34KFpawFgoqTu/2EEH4Xl1zo+0fy73fEto+nfkUY+meuyGZ1nUx/+DljP7ZqxHBFSlLODmtuTMdswUbHbXbWneW51D7Jm7xB8nSdiA2JQNK5+Sg5x8aNfgvFTt/m2w2+qpsyFa5Wjeu6fZmXSl840CA07aXbk9vN4I81WmJyblD/ZA=="
 	PrivateKey   = `
@@ -1336,3 +1405,69 @@ func TestNewMockFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctions_terraformVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		src     string
+		want    cty.Value
+	}{
+		{
+			version: "1.2.9",
+			src:     `startswith("foobar", "foo")`,
+			want:    cty.DynamicVal,
+		},
+		{
+			version: "1.3.0",
+			src:     `startswith("foobar", "foo")`,
+			want:    cty.True,
+		},
+		{
+			version: "1.9.0-beta1",
+			src:     `templatestring(local.greeting_template, { name = "Jane" })`,
+			want:    cty.StringVal("Hello, Jane!"),
+		},
+		{
+			version: "1.7.5",
+			src:     `core::upper("foo")`,
+			want:    cty.DynamicVal,
+		},
+		{
+			version: "1.8.0",
+			src:     `core::upper("foo")`,
+			want:    cty.StringVal("FOO"),
+		},
+		{
+			version: "1.0.0",
+			src:     `upper("foo")`,
+			want:    cty.StringVal("FOO"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %s", test.version, test.src), func(t *testing.T) {
+			scope := &Scope{
+				Data: &dataForTests{
+					LocalValues: map[string]cty.Value{
+						"greeting_template": cty.StringVal("Hello, ${name}!"),
+					},
+				},
+				TerraformVersion: version.Must(version.NewVersion(test.version)),
+			}
+
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(test.src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags)
+			}
+
+			got, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if !test.want.RawEquals(got) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty/function"
 
@@ -25,6 +26,20 @@ type Scope struct {
 	// accept filesystem paths as arguments.
 	BaseDir string
 
+	// SandboxDir is the directory that filesystem functions can access.
+	// If empty, filesystem functions can access any paths.
+	SandboxDir string
+
+	// DeterministicResults are the fixed results of impure functions keyed by
+	// the function name. Functions in this map return the string value instead
+	// of executing, so that expressions containing them are reproducible.
+	DeterministicResults map[string]string
+
+	// TerraformVersion is the Terraform version targeted by the configuration.
+	// Functions not available in the version return unknown values. If nil,
+	// all functions are available.
+	TerraformVersion *version.Version
+
 	// PureOnly can be set to true to request that any non-pure functions
 	// produce unknown value results rather than actually executing. This is
 	// important during a plan phase to avoid generating results that could
@@ -51,19 +66,21 @@ func NewCallStack() *CallStack {
 	}
 }
 
+// Push pushes the given reference onto the stack.
+// If the reference is already in the stack, it returns an error
+// and the stack is left unchanged.
 func (g *CallStack) Push(addr addrs.Reference) hcl.Diagnostics {
-	g.stack = append(g.stack, addr.Subject.String())
-
 	if _, exists := g.addrs[addr.Subject.String()]; exists {
 		return hcl.Diagnostics{
 			{
 				Severity: hcl.DiagError,
 				Summary:  "circular reference found",
-				Detail:   g.String(),
+				Detail:   strings.Join(append(g.stack, addr.Subject.String()), " -> "),
 				Subject:  addr.SourceRange.Ptr(),
 			},
 		}
 	}
+	g.stack = append(g.stack, addr.Subject.String())
 	g.addrs[addr.Subject.String()] = addr
 	return hcl.Diagnostics{}
 }
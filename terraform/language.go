@@ -0,0 +1,48 @@
+package terraform
+
+import "fmt"
+
+// Language is a type of configuration language to be parsed.
+// This is primarily used to control which configuration files are loaded.
+type Language int32
+
+const (
+	// LanguageAuto detects the language from the files in a module directory.
+	// If OpenTofu files (.tofu/.tofu.json) exist, they are loaded like OpenTofu.
+	// Since Terraform never loads these files, this is currently equivalent to
+	// LanguageOpenTofu when loading files.
+	LanguageAuto Language = iota
+
+	// LanguageTerraform loads only Terraform files (.tf/.tf.json).
+	LanguageTerraform
+
+	// LanguageOpenTofu loads Terraform files and OpenTofu files (.tofu/.tofu.json).
+	// If a .tofu file has the same name as a .tf file, the .tf file is ignored.
+	LanguageOpenTofu
+)
+
+func AsLanguage(s string) (Language, error) {
+	switch s {
+	case "auto":
+		return LanguageAuto, nil
+	case "terraform":
+		return LanguageTerraform, nil
+	case "opentofu":
+		return LanguageOpenTofu, nil
+	default:
+		return LanguageAuto, fmt.Errorf("%s is invalid language. Allowed values are: auto, terraform, opentofu", s)
+	}
+}
+
+func (l Language) String() string {
+	switch l {
+	case LanguageAuto:
+		return "auto"
+	case LanguageTerraform:
+		return "terraform"
+	case LanguageOpenTofu:
+		return "opentofu"
+	default:
+		panic("never happened")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
@@ -21,6 +22,7 @@ import (
 type Loader struct {
 	parser  *Parser
 	modules moduleMgr
+	cache   *moduleCache
 
 	baseDir string
 }
@@ -31,7 +33,7 @@ type Loader struct {
 // The loader has some internal state about the modules that are currently
 // installed, which is read from disk as part of this function. Note that
 // this will always read against the current directory unless TF_DATA_DIR
-// is set.
+// is set. The module cache installed by TFLint itself is also read.
 //
 // If an original working dir is passed, the paths of the loaded files will
 // be relative to that directory.
@@ -61,9 +63,28 @@ func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
 		return nil, fmt.Errorf("failed to read module manifest: %s", err)
 	}
 
+	cacheDir, err := ModuleCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine module cache dir: %s", err)
+	}
+	cacheDir, err = filepath.Abs(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine module cache dir: %s", err)
+	}
+	ret.cache = &moduleCache{fs: fs, dir: cacheDir}
+	if err := ret.cache.readManifest(); err != nil {
+		return nil, fmt.Errorf("failed to read module cache: %s", err)
+	}
+
 	return ret, nil
 }
 
+// SetLanguage sets the configuration language to be loaded.
+// See also Parser.SetLanguage.
+func (l *Loader) SetLanguage(lang Language) {
+	l.parser.SetLanguage(lang)
+}
+
 // LoadConfig reads the Terraform module in the given directory and uses it as the
 // root module to build the static module tree that represents a configuration.
 func (l *Loader) LoadConfig(dir string, callModuleType CallModuleType) (*Config, hcl.Diagnostics) {
@@ -71,6 +92,10 @@ func (l *Loader) LoadConfig(dir string, callModuleType CallModuleType) (*Config,
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	diags = l.parser.LoadTestFiles(l.baseDir, mod)
+	if diags.HasErrors() {
+		return nil, diags
+	}
 
 	var walker ModuleWalkerFunc
 	switch callModuleType {
@@ -90,7 +115,8 @@ func (l *Loader) LoadConfig(dir string, callModuleType CallModuleType) (*Config,
 	if diags.HasErrors() {
 		return nil, diags
 	}
-	return cfg, nil
+	// Warnings, such as module drift from the manifest, are returned along with the config
+	return cfg, diags
 }
 
 func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
@@ -116,7 +142,7 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 					},
 				}
 			}
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+			mod, diags := l.loadModuleDir(dir)
 			return mod, nil, diags
 
 		case addrs.ModuleSourceRemote:
@@ -130,18 +156,29 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 			key := l.modules.manifest.moduleKey(req.Path)
 			record, exists := l.modules.manifest[key]
 			if !exists {
+				// If the module is not installed by Terraform, fall back to the module cache
+				// installed by "tflint --init". The cache is never updated here, so linting
+				// does not require network access.
+				if cached := l.cache.lookup(source.String(), req.VersionConstraint.Required); cached != nil {
+					dir := l.cache.path(cached)
+					log.Printf("[DEBUG] Trying to load the cached module: key=%s, version=%s, dir=%s", key, cached.VersionStr, dir)
+					mod, diags := l.loadModuleDir(dir)
+					return mod, cached.Version, diags
+				}
+
 				log.Printf(`[DEBUG] Failed to find "%s"`, key)
 				return nil, nil, hcl.Diagnostics{
 					{
 						Severity: hcl.DiagError,
-						Summary:  fmt.Sprintf(`"%s" module is not found. Did you run "terraform init"?`, req.Name),
+						Summary:  fmt.Sprintf(`"%s" module is not found. Did you run "terraform init" or "tflint --init"?`, req.Name),
 						Subject:  &req.CallRange,
 					},
 				}
 			}
+			diags := checkModuleManifestRecord(req, record)
 			log.Printf("[DEBUG] Trying to load the remote module: key=%s, version=%s, dir=%s", key, record.VersionStr, record.Dir)
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, record.Dir)
-			return mod, record.Version, diags
+			mod, loadDiags := l.parser.LoadConfigDir(l.baseDir, record.Dir)
+			return mod, record.Version, diags.Extend(loadDiags)
 
 		default:
 			panic(fmt.Sprintf("unexpected module source type: %T", req.SourceAddr))
@@ -149,6 +186,128 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 	}
 }
 
+// checkModuleManifestRecord checks that the module installed by Terraform is still in agreement
+// with the module call. If the source address or version constraint has changed since "terraform init",
+// it returns a warning prompting to run it again. The installed module is still inspected,
+// so that a stale module directory does not prevent linting.
+func checkModuleManifestRecord(req *ModuleRequest, record *moduleRecord) hcl.Diagnostics {
+	if !moduleSourcesEqual(record.Source, req.SourceAddr.String()) {
+		return hcl.Diagnostics{
+			{
+				Severity: hcl.DiagWarning,
+				Summary:  "Module source has changed",
+				Detail:   fmt.Sprintf(`The source address of "%s" module was changed from "%s" to "%s" since it was installed. Run "terraform init" to install all modules required by this configuration.`, req.Name, record.Source, req.SourceAddr),
+				Subject:  &req.SourceAddrRange,
+			},
+		}
+	}
+
+	if record.Version != nil && len(req.VersionConstraint.Required) > 0 && !req.VersionConstraint.Required.Check(record.Version) {
+		return hcl.Diagnostics{
+			{
+				Severity: hcl.DiagWarning,
+				Summary:  "Module version requirements have changed",
+				Detail:   fmt.Sprintf(`The version requirements of "%s" module have changed since it was installed and the installed version (%s) is no longer acceptable. Run "terraform init" to install all modules required by this configuration.`, req.Name, record.Version),
+				Subject:  &req.VersionConstraint.DeclRange,
+			},
+		}
+	}
+
+	return nil
+}
+
+// moduleSourcesEqual returns true if the given source addresses refer to the same module.
+// Terraform records registry addresses with the default hostname in the manifest
+// (e.g. "registry.terraform.io/hashicorp/consul/aws"), so registry addresses are normalized.
+func moduleSourcesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	addrA, okA := parseRegistryModuleSource(a)
+	addrB, okB := parseRegistryModuleSource(b)
+	return okA && okB && *addrA == *addrB
+}
+
+// loadModuleDir loads the module in the given directory. Modules in the module cache
+// are placed outside the working directory and are loaded with absolute paths,
+// so the base dir is not joined in that case.
+func (l *Loader) loadModuleDir(dir string) (*Module, hcl.Diagnostics) {
+	if filepath.IsAbs(dir) {
+		return l.parser.LoadConfigDir("", dir)
+	}
+	return l.parser.LoadConfigDir(l.baseDir, dir)
+}
+
+// InstalledModule is a remote module installed by InstallModules.
+type InstalledModule struct {
+	// Key is the module call path (e.g. "network.subnets").
+	Key     string
+	Source  string
+	Version *version.Version
+	// Cached is true if the module had already been installed.
+	Cached bool
+}
+
+// InstallModules installs remote modules called from the module in the given directory
+// into the module cache, so that they can be loaded without "terraform init". Registry modules
+// are resolved to the latest version satisfying the version constraints.
+//
+// Modules already installed by Terraform are not installed again. Nested modules
+// are installed recursively.
+func (l *Loader) InstallModules(dir string) ([]*InstalledModule, hcl.Diagnostics) {
+	mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	installer := newModuleInstaller(l.cache)
+	installed := []*InstalledModule{}
+	load := l.moduleWalkerFunc(true, true)
+
+	walker := ModuleWalkerFunc(func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+		source, ok := req.SourceAddr.(addrs.ModuleSourceRemote)
+		if !ok {
+			return load(req)
+		}
+		key := l.modules.manifest.moduleKey(req.Path)
+		if _, exists := l.modules.manifest[key]; exists {
+			return load(req)
+		}
+
+		record, cached, err := installer.install(source.String(), req.VersionConstraint.Required)
+		if err != nil {
+			return nil, nil, hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf(`Failed to install "%s" module`, req.Name),
+					Detail:   err.Error(),
+					Subject:  &req.CallRange,
+				},
+			}
+		}
+		installed = append(installed, &InstalledModule{Key: key, Source: source.String(), Version: record.Version, Cached: cached})
+
+		mod, diags := l.loadModuleDir(l.cache.path(record))
+		return mod, record.Version, diags
+	})
+
+	_, buildDiags := BuildConfig(mod, walker)
+	diags = diags.Extend(buildDiags)
+
+	if !slices.ContainsFunc(installed, func(m *InstalledModule) bool { return !m.Cached }) {
+		return installed, diags
+	}
+	if err := l.cache.writeManifest(); err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to write module cache manifest",
+			Detail:   err.Error(),
+		})
+	}
+
+	return installed, diags
+}
+
 var defaultVarsFilename = "terraform.tfvars"
 
 // LoadValuesFiles reads Terraform's autoloaded values files in the given directory
@@ -171,14 +330,14 @@ func (l *Loader) LoadValuesFiles(dir string, files ...string) ([]InputValues, hc
 	}
 
 	for _, file := range autoLoadFiles {
-		vals, loadDiags := l.loadValuesFile(file)
+		vals, loadDiags := l.loadValuesFile(file, ValueFromAutoFile)
 		diags = diags.Extend(loadDiags)
 		if !loadDiags.HasErrors() {
 			values = append(values, vals)
 		}
 	}
 	for _, file := range files {
-		vals, loadDiags := l.loadValuesFile(file)
+		vals, loadDiags := l.loadValuesFile(file, ValueFromNamedFile)
 		diags = diags.Extend(loadDiags)
 		if !loadDiags.HasErrors() {
 			values = append(values, vals)
@@ -188,19 +347,15 @@ func (l *Loader) LoadValuesFiles(dir string, files ...string) ([]InputValues, hc
 	return values, diags
 }
 
-func (l *Loader) loadValuesFile(file string) (InputValues, hcl.Diagnostics) {
-	vals, diags := l.parser.LoadValuesFile(l.baseDir, file)
+func (l *Loader) loadValuesFile(file string, sourceType ValueSourceType) (InputValues, hcl.Diagnostics) {
+	vals, diags := l.parser.loadValuesFile(l.baseDir, file)
 	if diags.HasErrors() {
 		return nil, diags
 	}
-
-	ret := make(InputValues)
-	for k, v := range vals {
-		ret[k] = &InputValue{
-			Value: v,
-		}
+	for _, val := range vals {
+		val.SourceType = sourceType
 	}
-	return ret, nil
+	return vals, nil
 }
 
 func (l *Loader) LoadConfigDirFiles(dir string) (map[string]*hcl.File, hcl.Diagnostics) {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -167,6 +168,8 @@ func TestLoadConfig_callLocalModules(t *testing.T) {
 }
 
 func TestLoadConfig_withoutModuleManifest(t *testing.T) {
+	t.Setenv("TFLINT_MODULE_CACHE_DIR", t.TempDir())
+
 	withinFixtureDir(t, "without_module_manifest", func(dir string) {
 		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
 		if err != nil {
@@ -177,13 +180,280 @@ func TestLoadConfig_withoutModuleManifest(t *testing.T) {
 			t.Fatal("Expected error is not occurred")
 		}
 
-		expected := `module.tf:6,1-16: "consul" module is not found. Did you run "terraform init"?; `
+		expected := `module.tf:6,1-16: "consul" module is not found. Did you run "terraform init" or "tflint --init"?; `
 		if diags.Error() != expected {
 			t.Fatalf(`Expected error is "%s", but got "%s"`, expected, diags)
 		}
 	})
 }
 
+func TestLoadConfig_moduleManifestDrift(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name: "up to date",
+			config: `
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "~> 0.9"
+}`,
+		},
+		{
+			name: "source changed",
+			config: `
+module "consul" {
+  source  = "hashicorp/vault/aws"
+  version = "0.9.0"
+}`,
+			want: `main.tf:3,13-34: Module source has changed; The source address of "consul" module was changed from "registry.terraform.io/hashicorp/consul/aws" to "hashicorp/vault/aws" since it was installed. Run "terraform init" to install all modules required by this configuration.`,
+		},
+		{
+			name: "version changed",
+			config: `
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = ">= 1.0"
+}`,
+			want: `main.tf:4,3-21: Module version requirements have changed; The version requirements of "consul" module have changed since it was installed and the installed version (0.9.0) is no longer acceptable. Run "terraform init" to install all modules required by this configuration.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("TFLINT_MODULE_CACHE_DIR", t.TempDir())
+
+			dir := t.TempDir()
+			files := map[string]string{
+				"main.tf": test.config,
+				".terraform/modules/modules.json": `{"Modules":[
+  {"Key": "", "Source": "", "Dir": "."},
+  {"Key": "consul", "Source": "registry.terraform.io/hashicorp/consul/aws", "Version": "0.9.0", "Dir": ".terraform/modules/consul"}
+]}`,
+				".terraform/modules/consul/main.tf": ``,
+			}
+			for name, src := range files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			currentDir, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := os.Chdir(currentDir); err != nil {
+					t.Fatal(err)
+				}
+			}()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, diags := loader.LoadConfig(".", CallAllModule)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			// The installed module is loaded even if it is stale
+			if _, exists := config.Children["consul"]; !exists {
+				t.Fatal("the installed module is not loaded")
+			}
+
+			if test.want == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected warnings: %s", diags)
+				}
+				return
+			}
+			if diags.Error() != test.want {
+				t.Errorf("want: %s, got: %s", test.want, diags)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_testFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+}`,
+		"main.tftest.hcl": `run "foo" {}`,
+		"child/main.tf":   ``,
+		"child/main.tftest.hcl": `
+run "invalid" {
+  command = destroy
+}`,
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, diags := loader.LoadConfig(".", CallLocalModule)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if _, exists := config.Module.Tests["main.tftest.hcl"]; !exists {
+		t.Error("test files in the root module should be loaded")
+	}
+	// Test files in called modules are not loaded, so the invalid test file is not an error
+	if len(config.Children["child"].Module.Tests) > 0 {
+		t.Error("test files in called modules should not be loaded")
+	}
+}
+
+func TestLoadConfig_moduleCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("TFLINT_MODULE_CACHE_DIR", cacheDir)
+
+	files := map[string]string{
+		"modules.json": `{"Modules":[
+  {"Source": "hashicorp/consul/aws", "Version": "0.8.0", "Dir": "consul-0.8.0"},
+  {"Source": "hashicorp/consul/aws", "Version": "0.9.0", "Dir": "consul-0.9.0"},
+  {"Source": "hashicorp/consul/aws", "Version": "1.0.0", "Dir": "consul-1.0.0"}
+]}`,
+		"consul-0.8.0/main.tf":                 ``,
+		"consul-0.9.0/main.tf":                 `module "cluster" { source = "./modules/cluster" }`,
+		"consul-0.9.0/modules/cluster/main.tf": `variable "cluster_name" {}`,
+		"consul-1.0.0/main.tf":                 ``,
+	}
+	for name, src := range files {
+		path := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withinFixtureDir(t, "without_module_manifest", func(dir string) {
+		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, diags := loader.LoadConfig(".", CallAllModule)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		// module.consul is resolved to the version satisfying the constraint
+		testChildModule(t, config, "consul", filepath.Join(cacheDir, "consul-0.9.0"))
+		// module.consul.module.cluster is a local module in the cached module
+		testChildModule(t, config.Children["consul"], "cluster", filepath.Join(cacheDir, "consul-0.9.0", "modules", "cluster"))
+	})
+}
+
+func TestInstallModules(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("TFLINT_MODULE_CACHE_DIR", cacheDir)
+
+	pkgDir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(pkgDir, "modules", "app", "main.tf"):    `module "shared" { source = "../shared" }`,
+		filepath.Join(pkgDir, "modules", "shared", "main.tf"): `variable "name" {}`,
+	}
+	for path, src := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootDir := t.TempDir()
+	source := fmt.Sprintf("file::%s//modules/app", filepath.ToSlash(pkgDir))
+	if err := os.WriteFile(filepath.Join(rootDir, "main.tf"), []byte(fmt.Sprintf(`module "app" { source = %q }`, source)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(rootDir); err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed, diags := loader.InstallModules(".")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	want := []*InstalledModule{{Key: "app", Source: source}}
+	if diff := cmp.Diff(want, installed); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Modules are loaded from the cache with a new loader
+	loader, err = NewLoader(afero.Afero{Fs: afero.NewOsFs()}, rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, diags := loader.LoadConfig(".", CallAllModule)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if _, exists := config.Children["app"].Children["shared"]; !exists {
+		t.Fatalf("module.app.module.shared is not loaded: %#v", config.Children["app"].Children)
+	}
+
+	// Installed modules are not installed again
+	installed, diags = loader.InstallModules(".")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	want = []*InstalledModule{{Key: "app", Source: source, Cached: true}}
+	if diff := cmp.Diff(want, installed); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 func TestLoadConfig_withoutModuleManifest_callLocalModules(t *testing.T) {
 	withinFixtureDir(t, "without_module_manifest", func(dir string) {
 		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
@@ -317,27 +587,37 @@ func TestLoadValuesFiles(t *testing.T) {
 		expected := []InputValues{
 			{
 				"default": {
-					Value: cty.StringVal("terraform.tfvars"),
+					Value:       cty.StringVal("terraform.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: "terraform.tfvars", Start: hcl.Pos{Line: 1, Column: 11, Byte: 10}, End: hcl.Pos{Line: 1, Column: 29, Byte: 28}},
 				},
 			},
 			{
 				"auto1": {
-					Value: cty.StringVal("auto1.auto.tfvars"),
+					Value:       cty.StringVal("auto1.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: "auto1.auto.tfvars", Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"auto2": {
-					Value: cty.StringVal("auto2.auto.tfvars"),
+					Value:       cty.StringVal("auto2.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: "auto2.auto.tfvars", Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"cli1": {
-					Value: cty.StringVal("cli1.tfvars"),
+					Value:       cty.StringVal("cli1.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: "cli1.tfvars", Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 			{
 				"cli2": {
-					Value: cty.StringVal("cli2.tfvars"),
+					Value:       cty.StringVal("cli2.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: "cli2.tfvars", Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 		}
@@ -384,27 +664,37 @@ func TestLoadValuesFiles_withBaseDir(t *testing.T) {
 		expected := []InputValues{
 			{
 				"default": {
-					Value: cty.StringVal("terraform.tfvars"),
+					Value:       cty.StringVal("terraform.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "terraform.tfvars"), Start: hcl.Pos{Line: 1, Column: 11, Byte: 10}, End: hcl.Pos{Line: 1, Column: 29, Byte: 28}},
 				},
 			},
 			{
 				"auto1": {
-					Value: cty.StringVal("auto1.auto.tfvars"),
+					Value:       cty.StringVal("auto1.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "auto1.auto.tfvars"), Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"auto2": {
-					Value: cty.StringVal("auto2.auto.tfvars"),
+					Value:       cty.StringVal("auto2.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "auto2.auto.tfvars"), Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"cli1": {
-					Value: cty.StringVal("cli1.tfvars"),
+					Value:       cty.StringVal("cli1.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "cli1.tfvars"), Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 			{
 				"cli2": {
-					Value: cty.StringVal("cli2.tfvars"),
+					Value:       cty.StringVal("cli2.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "cli2.tfvars"), Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 		}
@@ -449,27 +739,37 @@ func TestLoadValuesFiles_withArgDir(t *testing.T) {
 		expected := []InputValues{
 			{
 				"default": {
-					Value: cty.StringVal("terraform.tfvars"),
+					Value:       cty.StringVal("terraform.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "terraform.tfvars"), Start: hcl.Pos{Line: 1, Column: 11, Byte: 10}, End: hcl.Pos{Line: 1, Column: 29, Byte: 28}},
 				},
 			},
 			{
 				"auto1": {
-					Value: cty.StringVal("auto1.auto.tfvars"),
+					Value:       cty.StringVal("auto1.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "auto1.auto.tfvars"), Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"auto2": {
-					Value: cty.StringVal("auto2.auto.tfvars"),
+					Value:       cty.StringVal("auto2.auto.tfvars"),
+					SourceType:  ValueFromAutoFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "auto2.auto.tfvars"), Start: hcl.Pos{Line: 1, Column: 9, Byte: 8}, End: hcl.Pos{Line: 1, Column: 28, Byte: 27}},
 				},
 			},
 			{
 				"cli1": {
-					Value: cty.StringVal("cli1.tfvars"),
+					Value:       cty.StringVal("cli1.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "cli1.tfvars"), Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 			{
 				"cli2": {
-					Value: cty.StringVal("cli2.tfvars"),
+					Value:       cty.StringVal("cli2.tfvars"),
+					SourceType:  ValueFromNamedFile,
+					SourceRange: hcl.Range{Filename: filepath.Join("values_files", "cli2.tfvars"), Start: hcl.Pos{Line: 1, Column: 8, Byte: 7}, End: hcl.Pos{Line: 1, Column: 21, Byte: 20}},
 				},
 			},
 		}
@@ -11,10 +11,21 @@ import (
 )
 
 type Module struct {
-	Resources   map[string]map[string]*Resource
-	Variables   map[string]*Variable
-	Locals      map[string]*Local
-	ModuleCalls map[string]*ModuleCall
+	Resources     map[string]map[string]*Resource
+	DataResources map[string]map[string]*Resource
+	Variables     map[string]*Variable
+	Locals        map[string]*Local
+	Outputs       map[string]*Output
+	ModuleCalls   map[string]*ModuleCall
+
+	EphemeralResources map[string]map[string]*Resource
+
+	Checks map[string]*Check
+
+	// Tests are the test files (*.tftest.hcl) keyed by the file path.
+	// Note that these files are not included in Sources and Files,
+	// as they are not part of the module configuration.
+	Tests map[string]*TestFile
 
 	SourceDir string
 
@@ -24,14 +35,23 @@ type Module struct {
 	primaries         map[string]*hcl.File
 	overrides         map[string]*hcl.File
 	overrideFilenames []string
+	tests             map[string]*hcl.File
 }
 
 func NewEmptyModule() *Module {
 	return &Module{
-		Resources:   map[string]map[string]*Resource{},
-		Variables:   map[string]*Variable{},
-		Locals:      map[string]*Local{},
-		ModuleCalls: map[string]*ModuleCall{},
+		Resources:     map[string]map[string]*Resource{},
+		DataResources: map[string]map[string]*Resource{},
+		Variables:     map[string]*Variable{},
+		Locals:        map[string]*Local{},
+		Outputs:       map[string]*Output{},
+		ModuleCalls:   map[string]*ModuleCall{},
+
+		EphemeralResources: map[string]map[string]*Resource{},
+
+		Checks: map[string]*Check{},
+
+		Tests: map[string]*TestFile{},
 
 		SourceDir: "",
 
@@ -41,6 +61,7 @@ func NewEmptyModule() *Module {
 		primaries:         map[string]*hcl.File{},
 		overrides:         map[string]*hcl.File{},
 		overrideFilenames: []string{},
+		tests:             map[string]*hcl.File{},
 	}
 }
 
@@ -58,6 +79,18 @@ func (m *Module) build() hcl.Diagnostics {
 				m.Resources[r.Type] = map[string]*Resource{}
 			}
 			m.Resources[r.Type][r.Name] = r
+		case "data":
+			r := decodeDataBlock(block)
+			if _, exists := m.DataResources[r.Type]; !exists {
+				m.DataResources[r.Type] = map[string]*Resource{}
+			}
+			m.DataResources[r.Type][r.Name] = r
+		case "ephemeral":
+			r := decodeEphemeralBlock(block)
+			if _, exists := m.EphemeralResources[r.Type]; !exists {
+				m.EphemeralResources[r.Type] = map[string]*Resource{}
+			}
+			m.EphemeralResources[r.Type][r.Name] = r
 		case "variable":
 			v, valDiags := decodeVairableBlock(block)
 			diags = diags.Extend(valDiags)
@@ -71,9 +104,30 @@ func (m *Module) build() hcl.Diagnostics {
 			for _, local := range locals {
 				m.Locals[local.Name] = local
 			}
+		case "output":
+			o, outputDiags := decodeOutputBlock(block)
+			diags = diags.Extend(outputDiags)
+			m.Outputs[o.Name] = o
+		case "check":
+			c := decodeCheckBlock(block)
+			m.Checks[c.Name] = c
 		}
 	}
 
+	diags = diags.Extend(m.buildTests())
+
+	return diags
+}
+
+func (m *Module) buildTests() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for path, f := range m.tests {
+		tf, testDiags := decodeTestFile(path, f.Body)
+		diags = diags.Extend(testDiags)
+		m.Tests[path] = tf
+	}
+
 	return diags
 }
 
@@ -98,6 +152,11 @@ func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
 			continue
 		}
 
+		if _, exists := m.tests[path]; exists {
+			m.tests[path] = file
+			continue
+		}
+
 		m.Sources[path] = source
 		m.Files[path] = file
 		if _, exists := m.primaries[path]; exists {
@@ -467,6 +526,17 @@ var moduleSchema = &hclext.BodySchema{
 		{
 			Type:       "resource",
 			LabelNames: []string{"type", "name"},
+			Body:       resourceBlockSchema,
+		},
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+			Body:       resourceBlockSchema,
+		},
+		{
+			Type:       "ephemeral",
+			LabelNames: []string{"type", "name"},
+			Body:       resourceBlockSchema,
 		},
 		{
 			Type:       "variable",
@@ -482,5 +552,15 @@ var moduleSchema = &hclext.BodySchema{
 			Type: "locals",
 			Body: localBlockSchema,
 		},
+		{
+			Type:       "output",
+			LabelNames: []string{"name"},
+			Body:       outputBlockSchema,
+		},
+		{
+			Type:       "check",
+			LabelNames: []string{"name"},
+			Body:       checkBlockSchema,
+		},
 	},
 }
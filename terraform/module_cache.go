@@ -0,0 +1,164 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/afero"
+)
+
+// ModuleCacheRoot is the default directory of the module cache.
+// This variable is exposed for testing.
+var ModuleCacheRoot = "~/.tflint.d/modules"
+
+// ModuleCacheDir returns the directory of the module cache.
+// Adopted with the following priorities:
+//
+//  1. `TFLINT_MODULE_CACHE_DIR` environment variable
+//  2. Home directory (~/.tflint.d/modules)
+func ModuleCacheDir() (string, error) {
+	if dir := os.Getenv("TFLINT_MODULE_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	return homedir.Expand(ModuleCacheRoot)
+}
+
+// moduleCache manages remote modules installed by TFLint itself, without "terraform init".
+// Unlike Terraform's manifest, records are keyed by the source address and version, not by
+// the module call path, so that installed modules can be shared between root modules.
+type moduleCache struct {
+	fs      afero.Afero
+	dir     string
+	records []*moduleCacheRecord
+}
+
+// moduleCacheRecord describes the structure of the manifest file of the module cache.
+// The Dir is relative to the cache directory.
+type moduleCacheRecord struct {
+	Source     string           `json:"Source"`
+	Version    *version.Version `json:"-"`
+	VersionStr string           `json:"Version,omitempty"`
+	Dir        string           `json:"Dir"`
+}
+
+type moduleCacheManifestFile struct {
+	Records []*moduleCacheRecord `json:"Modules"`
+}
+
+func (c *moduleCache) manifestPath() string {
+	return filepath.Join(c.dir, "modules.json")
+}
+
+func (c *moduleCache) readManifest() error {
+	r, err := c.fs.Open(c.manifestPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			// We'll treat a missing file as an empty cache
+			c.records = []*moduleCacheRecord{}
+			return nil
+		}
+		return err
+	}
+
+	log.Printf("[INFO] Module cache found in %s. Initializing...", c.dir)
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var read moduleCacheManifestFile
+	if err := json.Unmarshal(src, &read); err != nil {
+		return fmt.Errorf("error unmarshalling module cache manifest file: %v", err)
+	}
+
+	for _, record := range read.Records {
+		if record.VersionStr != "" {
+			record.Version, err = version.NewVersion(record.VersionStr)
+			if err != nil {
+				return fmt.Errorf("invalid version %q for %s: %s", record.VersionStr, record.Source, err)
+			}
+		}
+	}
+	c.records = read.Records
+
+	return nil
+}
+
+func (c *moduleCache) writeManifest() error {
+	src, err := json.MarshalIndent(moduleCacheManifestFile{Records: c.records}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := c.fs.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	return c.fs.WriteFile(c.manifestPath(), src, 0o644)
+}
+
+// lookup returns the installed module that matches the given source address and
+// satisfies the version constraints. If multiple versions are installed,
+// the latest one is returned. Returns nil if not found.
+func (c *moduleCache) lookup(source string, constraints version.Constraints) *moduleCacheRecord {
+	var ret *moduleCacheRecord
+
+	for _, record := range c.records {
+		if record.Source != source {
+			continue
+		}
+		if record.Version == nil {
+			if len(constraints) == 0 {
+				return record
+			}
+			continue
+		}
+		if !constraints.Check(record.Version) {
+			continue
+		}
+		if ret == nil || record.Version.GreaterThan(ret.Version) {
+			ret = record
+		}
+	}
+
+	return ret
+}
+
+// add adds a new record for the module installed in the given directory.
+// The directory must be under the cache directory.
+func (c *moduleCache) add(source string, v *version.Version, dir string) (*moduleCacheRecord, error) {
+	rel, err := filepath.Rel(c.dir, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &moduleCacheRecord{Source: source, Version: v, Dir: filepath.ToSlash(rel)}
+	if v != nil {
+		record.VersionStr = v.String()
+	}
+	c.records = append(c.records, record)
+
+	return record, nil
+}
+
+// packageDir returns the directory where the module package is installed.
+// This is determined by the package address and version, not the module call,
+// so that the same package is installed only once.
+func (c *moduleCache) packageDir(packageAddr string, v *version.Version) string {
+	key := packageAddr
+	if v != nil {
+		key += "@" + v.String()
+	}
+	return filepath.Join(c.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(key))))
+}
+
+// path returns the absolute path of the module directory in the record.
+func (c *moduleCache) path(record *moduleCacheRecord) string {
+	return filepath.Join(c.dir, filepath.FromSlash(record.Dir))
+}
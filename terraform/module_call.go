@@ -5,17 +5,25 @@ package terraform
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
 	"github.com/nholuongut/tflint/terraform/addrs"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ModuleCall struct {
-	Name          string
-	SourceAddr    addrs.ModuleSource
-	SourceAddrRaw string
+	Name            string
+	SourceAddr      addrs.ModuleSource
+	SourceAddrRaw   string
+	SourceAddrRange hcl.Range
+
+	Version VersionConstraint
+
+	Count   hcl.Expression
+	ForEach hcl.Expression
 
 	DeclRange hcl.Range
 }
@@ -31,6 +39,7 @@ func decodeModuleBlock(block *hclext.Block) (*ModuleCall, hcl.Diagnostics) {
 	if attr, exists := block.Body.Attributes["source"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &mc.SourceAddrRaw)
 		diags = diags.Extend(valDiags)
+		mc.SourceAddrRange = attr.Expr.Range()
 
 		if !diags.HasErrors() {
 			var err error
@@ -46,17 +55,104 @@ func decodeModuleBlock(block *hclext.Block) (*ModuleCall, hcl.Diagnostics) {
 		}
 	}
 
+	if attr, exists := block.Body.Attributes["version"]; exists {
+		var versionDiags hcl.Diagnostics
+		mc.Version, versionDiags = decodeVersionConstraint(attr)
+		diags = diags.Extend(versionDiags)
+	}
+
+	if attr, exists := block.Body.Attributes["count"]; exists {
+		mc.Count = attr.Expr
+	}
+
+	if attr, exists := block.Body.Attributes["for_each"]; exists {
+		mc.ForEach = attr.Expr
+	}
+
 	return mc, diags
 }
 
+// CheckModuleCallArguments returns errors for the arguments of the given module call
+// that do not match the variables declared in the called module. Arguments that are not
+// declared as variables and required variables without arguments are reported.
+//
+// The args should contain all arguments in the module block, including meta-arguments.
+// Type constraints are not checked here since it requires evaluating the arguments.
+func CheckModuleCallArguments(call *ModuleCall, args hclext.Attributes, declVars map[string]*Variable) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	suggestions := make([]string, 0, len(declVars))
+	for name := range declVars {
+		suggestions = append(suggestions, name)
+	}
+	sort.Strings(suggestions)
+
+	argNames := make([]string, 0, len(args))
+	for name := range args {
+		argNames = append(argNames, name)
+	}
+	sort.Strings(argNames)
+
+	for _, name := range argNames {
+		if _, declared := declVars[name]; declared || moduleMetaArguments[name] {
+			continue
+		}
+
+		detail := fmt.Sprintf("An argument named %q is not expected here. The module %q does not declare a variable of that name.", name, call.Name)
+		if suggestion := nameSuggestion(name, suggestions); suggestion != "" {
+			detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported argument",
+			Detail:   detail,
+			Subject:  args[name].NameRange.Ptr(),
+		})
+	}
+
+	for _, name := range suggestions {
+		if _, exists := args[name]; exists || declVars[name].Default != cty.NilVal {
+			continue
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing required argument",
+			Detail:   fmt.Sprintf("The argument %q is required by the module %q, but no definition was found. The variable is declared at %s.", name, call.Name, declVars[name].DeclRange),
+			Subject:  call.DeclRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
 var moduleBlockSchema = &hclext.BodySchema{
 	Attributes: []hclext.AttributeSchema{
 		{
 			Name: "source",
 		},
+		{
+			Name: "version",
+		},
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
 	},
 }
 
+// moduleMetaArguments are arguments in module blocks that are not input variables.
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"count":      true,
+	"for_each":   true,
+	"providers":  true,
+	"depends_on": true,
+}
+
 // CallModuleType is a type of module to call.
 // This is primarily used to control module walker behavior.
 type CallModuleType int32
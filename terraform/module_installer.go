@@ -0,0 +1,326 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/go-version"
+)
+
+// moduleInstaller installs remote modules into the module cache.
+// Registry modules are resolved via the module registry protocol, and other
+// sources such as Git repositories and archives are downloaded with go-getter,
+// just like Terraform does.
+type moduleInstaller struct {
+	cache    *moduleCache
+	registry *registryClient
+
+	// get downloads the package of the given source address into the directory.
+	// This is replaced in tests to avoid network access.
+	get func(dst, src string) error
+}
+
+func newModuleInstaller(cache *moduleCache) *moduleInstaller {
+	return &moduleInstaller{
+		cache:    cache,
+		registry: &registryClient{client: http.DefaultClient, scheme: "https", services: map[string]*url.URL{}},
+		get: func(dst, src string) error {
+			pwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return getter.Get(dst, src, getter.WithMode(getter.ClientModeDir), func(c *getter.Client) error {
+				c.Pwd = pwd
+				return nil
+			})
+		},
+	}
+}
+
+// install installs the module of the given source address into the module cache.
+// If the module is already installed, it returns the existing record and true.
+func (i *moduleInstaller) install(source string, constraints version.Constraints) (*moduleCacheRecord, bool, error) {
+	if addr, ok := parseRegistryModuleSource(source); ok {
+		return i.installRegistryModule(source, addr, constraints)
+	}
+
+	if len(constraints) > 0 {
+		return nil, false, fmt.Errorf("cannot apply a version constraint to %s because it is not a registry module", source)
+	}
+	if record := i.cache.lookup(source, nil); record != nil {
+		return record, true, nil
+	}
+
+	packageAddr, subDir := getter.SourceDirSubdir(source)
+	dst := i.cache.packageDir(packageAddr, nil)
+	if err := i.getPackage(dst, packageAddr); err != nil {
+		return nil, false, err
+	}
+
+	record, err := i.cache.add(source, nil, filepath.Join(dst, filepath.FromSlash(subDir)))
+	return record, false, err
+}
+
+func (i *moduleInstaller) installRegistryModule(source string, addr *registryModuleAddr, constraints version.Constraints) (*moduleCacheRecord, bool, error) {
+	versions, err := i.registry.moduleVersions(addr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var latest *version.Version
+	for _, v := range versions {
+		if !constraints.Check(v) {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return nil, false, fmt.Errorf("no available versions of %s satisfy the constraints %q", addr.packageAddr(), constraints)
+	}
+
+	if record := i.cache.lookup(source, version.MustConstraints(version.NewConstraint("= "+latest.String()))); record != nil {
+		return record, true, nil
+	}
+
+	location, err := i.registry.moduleLocation(addr, latest)
+	if err != nil {
+		return nil, false, err
+	}
+	packageAddr, subDir := getter.SourceDirSubdir(location)
+
+	dst := i.cache.packageDir(addr.packageAddr(), latest)
+	if err := i.getPackage(dst, packageAddr); err != nil {
+		return nil, false, err
+	}
+
+	dir := filepath.Join(dst, filepath.FromSlash(subDir), filepath.FromSlash(addr.subDir))
+	record, err := i.cache.add(source, latest, dir)
+	return record, false, err
+}
+
+// getPackage downloads the package into the directory if it does not exist yet.
+// The same package can be referred to by multiple sources with different subdirectories.
+func (i *moduleInstaller) getPackage(dst, packageAddr string) error {
+	if _, err := i.cache.fs.Stat(dst); err == nil {
+		log.Printf("[DEBUG] Package %s is already downloaded in %s", packageAddr, dst)
+		return nil
+	}
+
+	log.Printf("[DEBUG] Downloading %s into %s", packageAddr, dst)
+	if err := i.get(dst, packageAddr); err != nil {
+		// Remove incomplete downloads so as not to be treated as installed.
+		_ = i.cache.fs.RemoveAll(dst)
+		return fmt.Errorf("failed to download %s: %w", packageAddr, err)
+	}
+	return nil
+}
+
+const defaultModuleRegistryHost = "registry.terraform.io"
+
+// registryModuleAddr is a module address in a module registry.
+// e.g. "hashicorp/consul/aws" or "app.terraform.io/example-corp/k8s-cluster/azurerm//modules/node"
+type registryModuleAddr struct {
+	host      string
+	namespace string
+	name      string
+	provider  string
+	subDir    string
+}
+
+var registryModulePartPattern = regexp.MustCompile(`^[0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?$`)
+
+// parseRegistryModuleSource parses the source address as a registry module address.
+// Returns false if the source is not a registry module address.
+func parseRegistryModuleSource(raw string) (*registryModuleAddr, bool) {
+	if strings.Contains(raw, "::") || strings.Contains(raw, "://") {
+		return nil, false
+	}
+
+	packageAddr, subDir := getter.SourceDirSubdir(raw)
+	parts := strings.Split(packageAddr, "/")
+
+	addr := &registryModuleAddr{host: defaultModuleRegistryHost, subDir: subDir}
+	switch len(parts) {
+	case 3:
+	case 4:
+		addr.host = parts[0]
+		parts = parts[1:]
+		// These hosts are shorthands for Git repositories in go-getter.
+		if addr.host == "github.com" || addr.host == "bitbucket.org" || !strings.ContainsAny(addr.host, ".:") {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	for _, part := range parts {
+		if !registryModulePartPattern.MatchString(part) {
+			return nil, false
+		}
+	}
+	addr.namespace, addr.name, addr.provider = parts[0], parts[1], parts[2]
+
+	return addr, true
+}
+
+func (a *registryModuleAddr) packageAddr() string {
+	return path.Join(a.host, a.namespace, a.name, a.provider)
+}
+
+// registryClient is a client for the module registry protocol.
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol
+type registryClient struct {
+	client *http.Client
+	scheme string
+
+	// services is the cache of discovered module service URLs keyed by hostname.
+	services map[string]*url.URL
+}
+
+// discover returns the base URL of the module registry service on the given host.
+func (c *registryClient) discover(host string) (*url.URL, error) {
+	if u, exists := c.services[host]; exists {
+		return u, nil
+	}
+
+	discoveryURL := &url.URL{Scheme: c.scheme, Host: host, Path: "/.well-known/terraform.json"}
+	var services map[string]any
+	if _, err := c.request(host, discoveryURL.String(), &services); err != nil {
+		return nil, fmt.Errorf("failed to discover module registry service on %s: %w", host, err)
+	}
+
+	raw, ok := services["modules.v1"].(string)
+	if !ok {
+		return nil, fmt.Errorf("host %s does not provide a module registry", host)
+	}
+	u, err := discoveryURL.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module registry service URL %q on %s: %w", raw, host, err)
+	}
+
+	c.services[host] = u
+	return u, nil
+}
+
+// moduleVersions returns the available versions of the module.
+func (c *registryClient) moduleVersions(addr *registryModuleAddr) ([]*version.Version, error) {
+	base, err := c.discover(addr.host)
+	if err != nil {
+		return nil, err
+	}
+	u, err := base.Parse(path.Join(addr.namespace, addr.name, addr.provider, "versions"))
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Modules []struct {
+			Versions []struct {
+				Version string `json:"version"`
+			} `json:"versions"`
+		} `json:"modules"`
+	}
+	if _, err := c.request(addr.host, u.String(), &res); err != nil {
+		return nil, fmt.Errorf("failed to fetch versions of %s: %w", addr.packageAddr(), err)
+	}
+
+	versions := []*version.Version{}
+	for _, mod := range res.Modules {
+		for _, raw := range mod.Versions {
+			v, err := version.NewVersion(raw.Version)
+			if err != nil {
+				log.Printf("[WARN] Ignore invalid version %q of %s: %s", raw.Version, addr.packageAddr(), err)
+				continue
+			}
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// moduleLocation returns the real source address of the module version,
+// which is returned in the X-Terraform-Get header.
+func (c *registryClient) moduleLocation(addr *registryModuleAddr, v *version.Version) (string, error) {
+	base, err := c.discover(addr.host)
+	if err != nil {
+		return "", err
+	}
+	u, err := base.Parse(path.Join(addr.namespace, addr.name, addr.provider, v.String(), "download"))
+	if err != nil {
+		return "", err
+	}
+
+	var res struct {
+		Location string `json:"location"`
+	}
+	header, err := c.request(addr.host, u.String(), &res)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the download location of %s %s: %w", addr.packageAddr(), v, err)
+	}
+
+	location := header.Get("X-Terraform-Get")
+	if location == "" {
+		location = res.Location
+	}
+	if location == "" {
+		return "", fmt.Errorf("registry did not return a download location for %s %s", addr.packageAddr(), v)
+	}
+
+	// The location can be relative to the download URL.
+	if strings.HasPrefix(location, "/") || strings.HasPrefix(location, "./") || strings.HasPrefix(location, "../") {
+		ref, err := u.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		location = ref.String()
+	}
+	return location, nil
+}
+
+// request sends a GET request and decodes the JSON response into the given value.
+// Responses without content (e.g. 204 No Content) are not decoded.
+//
+// Credentials can be set with TF_TOKEN_<hostname> environment variables like Terraform.
+// https://developer.hashicorp.com/terraform/cli/config/config-file#environment-variable-credentials
+func (c *registryClient) request(host string, u string, v any) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(registryTokenEnvName(host)); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// registryTokenEnvName returns the environment variable name for credentials of the host.
+// Periods are encoded as underscores, and hyphens as double underscores.
+func registryTokenEnvName(host string) string {
+	return "TF_TOKEN_" + strings.NewReplacer(".", "_", "-", "__").Replace(host)
+}
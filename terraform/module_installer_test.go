@@ -0,0 +1,141 @@
+package terraform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/afero"
+)
+
+func TestParseRegistryModuleSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   *registryModuleAddr
+	}{
+		{
+			source: "hashicorp/consul/aws",
+			want:   &registryModuleAddr{host: "registry.terraform.io", namespace: "hashicorp", name: "consul", provider: "aws"},
+		},
+		{
+			source: "hashicorp/consul/aws//modules/consul-cluster",
+			want:   &registryModuleAddr{host: "registry.terraform.io", namespace: "hashicorp", name: "consul", provider: "aws", subDir: "modules/consul-cluster"},
+		},
+		{
+			source: "app.terraform.io/example-corp/k8s-cluster/azurerm",
+			want:   &registryModuleAddr{host: "app.terraform.io", namespace: "example-corp", name: "k8s-cluster", provider: "azurerm"},
+		},
+		{
+			source: "github.com/hashicorp/example",
+		},
+		{
+			source: "github.com/hashicorp/example/modules",
+		},
+		{
+			source: "git::https://example.com/network.git",
+		},
+		{
+			source: "https://example.com/vpc-module.zip",
+		},
+		{
+			source: "hashicorp/consul/aws?ref=v1.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			got, ok := parseRegistryModuleSource(test.source)
+			if ok != (test.want != nil) {
+				t.Fatalf("want registry module: %t, got: %t", test.want != nil, ok)
+			}
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(registryModuleAddr{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestModuleInstaller_installRegistryModule(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/terraform.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"modules.v1": "/v1/modules/"}`)
+	})
+	mux.HandleFunc("/v1/modules/hashicorp/consul/aws/versions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"modules": [{"versions": [{"version": "0.8.0"}, {"version": "0.9.0"}, {"version": "1.0.0"}]}]}`)
+	})
+	mux.HandleFunc("/v1/modules/hashicorp/consul/aws/0.9.0/download", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Terraform-Get", "git::https://example.com/consul.git?ref=v0.9.0")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := serverURL.Host
+	t.Setenv(registryTokenEnvName(host), "secret")
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	cache := &moduleCache{fs: fs, dir: "/cache", records: []*moduleCacheRecord{}}
+	installer := &moduleInstaller{
+		cache:    cache,
+		registry: &registryClient{client: server.Client(), scheme: "http", services: map[string]*url.URL{}},
+	}
+
+	gets := []string{}
+	installer.get = func(dst, src string) error {
+		gets = append(gets, src)
+		return fs.WriteFile(filepath.Join(dst, "modules", "cluster", "main.tf"), []byte{}, 0o644)
+	}
+
+	source := host + "/hashicorp/consul/aws//modules/cluster"
+	constraints := version.MustConstraints(version.NewConstraint("~> 0.8"))
+
+	record, cached, err := installer.install(source, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached {
+		t.Error("the module should not be cached on the first install")
+	}
+	if record.VersionStr != "0.9.0" {
+		t.Errorf("want version 0.9.0, got %s", record.VersionStr)
+	}
+	if !strings.HasSuffix(record.Dir, "/modules/cluster") {
+		t.Errorf("want the subdirectory of the package, got %s", record.Dir)
+	}
+	if diff := cmp.Diff([]string{"git::https://example.com/consul.git?ref=v0.9.0"}, gets); diff != "" {
+		t.Error(diff)
+	}
+
+	record, cached, err = installer.install(source, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached {
+		t.Error("the module should be cached on the second install")
+	}
+	if len(gets) != 1 {
+		t.Errorf("the package should not be downloaded again, got %d downloads", len(gets))
+	}
+	if record.VersionStr != "0.9.0" {
+		t.Errorf("want version 0.9.0, got %s", record.VersionStr)
+	}
+
+	_, _, err = installer.install(source, version.MustConstraints(version.NewConstraint("> 2.0")))
+	if err == nil {
+		t.Fatal("an error was expected to occur, but it did not")
+	}
+}
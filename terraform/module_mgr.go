@@ -74,6 +74,12 @@ func (l *moduleMgr) readModuleManifest() error {
 	}
 
 	for _, record := range read.Records {
+		if record.VersionStr != "" {
+			record.Version, err = version.NewVersion(record.VersionStr)
+			if err != nil {
+				return fmt.Errorf("invalid version %q for %s: %s", record.VersionStr, record.Key, err)
+			}
+		}
 		l.manifest[record.Key] = record
 	}
 
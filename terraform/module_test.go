@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
+	"github.com/nholuongut/tflint/terraform/addrs"
 )
 
 func TestRebuild(t *testing.T) {
@@ -186,6 +187,85 @@ variable "bar" { default = "baz" }
 	}
 }
 
+func TestBuild(t *testing.T) {
+	files := map[string]string{
+		"main.tf": `
+resource "aws_instance" "main" {
+  count = 2
+}
+
+data "aws_ami" "main" {
+  for_each = toset(["a", "b"])
+}
+
+ephemeral "random_password" "main" {
+  length = 16
+}
+
+variable "token" {
+  ephemeral = true
+}
+
+output "id" {
+  value     = aws_instance.main[0].id
+  sensitive = true
+}
+
+check "health" {
+  data "http" "main" {
+    url = "https://example.com"
+  }
+
+  assert {
+    condition     = data.http.main.status_code == 200
+    error_message = "unhealthy"
+  }
+}`,
+		"main_override.tf": `
+output "id" {
+  sensitive = false
+}`,
+	}
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	for name, content := range files {
+		if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	parser := NewParser(fs)
+	mod, diags := parser.LoadConfigDir(".", ".")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if r := mod.Resources["aws_instance"]["main"]; r == nil || r.Count == nil || r.ForEach != nil {
+		t.Errorf("unexpected resource: %#v", r)
+	}
+	if r := mod.DataResources["aws_ami"]["main"]; r == nil || r.ForEach == nil || r.Mode != addrs.DataResourceMode {
+		t.Errorf("unexpected data resource: %#v", r)
+	}
+	if r := mod.EphemeralResources["random_password"]["main"]; r == nil || r.Mode != addrs.EphemeralResourceMode {
+		t.Errorf("unexpected ephemeral resource: %#v", r)
+	}
+	if v := mod.Variables["token"]; v == nil || !v.Ephemeral {
+		t.Errorf("unexpected variable: %#v", v)
+	}
+
+	if o := mod.Outputs["id"]; o == nil || o.Expr == nil || o.Sensitive {
+		t.Errorf("unexpected output: %#v", o)
+	}
+
+	check := mod.Checks["health"]
+	if check == nil || check.DataResource == nil || check.DataResource.Type != "http" || len(check.Asserts) != 1 {
+		t.Fatalf("unexpected check: %#v", check)
+	}
+	if check.Asserts[0].Condition == nil || check.Asserts[0].ErrorMessage == nil {
+		t.Errorf("unexpected assert: %#v", check.Asserts[0])
+	}
+}
+
 func TestPartialContent(t *testing.T) {
 	tests := []struct {
 		name   string
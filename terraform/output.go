@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/nholuongut/tflint-plugin-sdk/hclext"
+)
+
+type Output struct {
+	Name      string
+	Expr      hcl.Expression
+	Sensitive bool
+	Ephemeral bool
+
+	Preconditions []*CheckRule
+
+	DeclRange hcl.Range
+}
+
+func decodeOutputBlock(block *hclext.Block) (*Output, hcl.Diagnostics) {
+	o := &Output{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+	diags := hcl.Diagnostics{}
+
+	if attr, exists := block.Body.Attributes["value"]; exists {
+		o.Expr = attr.Expr
+	}
+
+	if attr, exists := block.Body.Attributes["sensitive"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Sensitive)
+		diags = diags.Extend(valDiags)
+	}
+
+	if attr, exists := block.Body.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Ephemeral)
+		diags = diags.Extend(valDiags)
+	}
+
+	for _, b := range block.Body.Blocks {
+		if b.Type == "precondition" {
+			o.Preconditions = append(o.Preconditions, decodeCheckRuleBlock(b))
+		}
+	}
+
+	return o, diags
+}
+
+var outputBlockSchema = &hclext.BodySchema{
+	Attributes: []hclext.AttributeSchema{
+		{
+			Name: "value",
+		},
+		{
+			Name: "sensitive",
+		},
+		{
+			Name: "ephemeral",
+		},
+	},
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "precondition",
+			Body: checkRuleBlockSchema,
+		},
+	},
+}
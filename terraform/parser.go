@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +14,10 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
+// defaultTestDir is the directory where test files are loaded in addition to
+// the module directory.
+const defaultTestDir = "tests"
+
 // Parser is a fork of configs.Parser. This is the main interface to read
 // configuration files and other related files from disk.
 //
@@ -21,6 +26,8 @@ import (
 type Parser struct {
 	fs afero.Afero
 	p  *hclparse.Parser
+
+	language Language
 }
 
 // NewParser creates and returns a new Parser that reads files from the given
@@ -37,8 +44,15 @@ func NewParser(fs afero.Fs) *Parser {
 	}
 }
 
+// SetLanguage sets the configuration language to be parsed.
+// The default is LanguageAuto.
+func (p *Parser) SetLanguage(lang Language) {
+	p.language = lang
+}
+
 // LoadConfigDir reads the .tf and .tf.json files in the given directory and
-// then combines these files into a single Module.
+// then combines these files into a single Module. Depending on the language,
+// the .tofu and .tofu.json files are also read.
 //
 // If this method returns nil, that indicates that the given directory does not
 // exist at all or could not be opened for some reason. Callers may wish to
@@ -52,8 +66,8 @@ func NewParser(fs afero.Fs) *Parser {
 // This file does not consider a directory with no files to be an error, and
 // will simply return an empty module in that case.
 //
-// .tf files are parsed using the HCL native syntax while .tf.json files are
-// parsed using the HCL JSON syntax.
+// .tf and .tofu files are parsed using the HCL native syntax while .tf.json
+// and .tofu.json files are parsed using the HCL JSON syntax.
 //
 // If a baseDir is passed, the loaded files are assumed to be loaded from that
 // directory. However, SourceDir does not contain baseDir because it affects
@@ -107,8 +121,33 @@ func (p *Parser) LoadConfigDir(baseDir, dir string) (*Module, hcl.Diagnostics) {
 	return mod, diags
 }
 
-// LoadConfigDirFiles reads the .tf and .tf.json files in the given directory and
-// then returns these files as a map of file path.
+// LoadTestFiles reads the test files (.tftest.hcl and .tftest.json) in the module
+// directory and its "tests" subdirectory, and stores them in Module.Tests.
+//
+// This is separated from LoadConfigDir because test files are only loaded for
+// the root module. Terraform does not run tests of called modules, so invalid
+// test files in them should not prevent inspection.
+func (p *Parser) LoadTestFiles(baseDir string, mod *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, path := range p.testDirFiles(mod.SourceDir) {
+		f, loadDiags := p.loadHCLFile(baseDir, path)
+		diags = diags.Extend(loadDiags)
+		if loadDiags.HasErrors() {
+			continue
+		}
+		mod.tests[filepath.Join(baseDir, path)] = f
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	return mod.buildTests()
+}
+
+// LoadConfigDirFiles reads the .tf and .tf.json files (and .tofu and .tofu.json
+// files depending on the language) in the given directory and then returns these
+// files as a map of file path.
 //
 // The difference with LoadConfigDir is that it returns hcl.File instead of
 // a single module. This is useful when parsing HCL files in a context outside of
@@ -160,12 +199,27 @@ func (p *Parser) LoadConfigDirFiles(baseDir, dir string) (map[string]*hcl.File,
 // If a baseDir is passed, the loaded file is assumed to be loaded from that
 // directory.
 func (p *Parser) LoadValuesFile(baseDir, path string) (map[string]cty.Value, hcl.Diagnostics) {
+	inputs, diags := p.loadValuesFile(baseDir, path)
+	if inputs == nil {
+		return nil, diags
+	}
+
+	vals := make(map[string]cty.Value, len(inputs))
+	for name, input := range inputs {
+		vals[name] = input.Value
+	}
+	return vals, diags
+}
+
+// loadValuesFile is the same as LoadValuesFile, but returns InputValues
+// that retain where each value is defined.
+func (p *Parser) loadValuesFile(baseDir, path string) (InputValues, hcl.Diagnostics) {
 	f, diags := p.loadHCLFile(baseDir, path)
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
-	vals := make(map[string]cty.Value)
+	vals := make(InputValues)
 	if f == nil || f.Body == nil {
 		return vals, diags
 	}
@@ -179,7 +233,10 @@ func (p *Parser) LoadValuesFile(baseDir, path string) (map[string]cty.Value, hcl
 	for name, attr := range attrs {
 		val, valDiags := attr.Expr.Value(nil)
 		diags = diags.Extend(valDiags)
-		vals[name] = val
+		vals[name] = &InputValue{
+			Value:       val,
+			SourceRange: attr.Expr.Range(),
+		}
 	}
 
 	return vals, diags
@@ -235,7 +292,7 @@ func (p *Parser) Files() map[string]*hcl.File {
 
 // IsConfigDir determines whether the given path refers to a directory that
 // exists and contains at least one Terraform config file (with a .tf or
-// .tf.json extension.)
+// .tf.json extension, or a .tofu or .tofu.json extension depending on the language.)
 func (p *Parser) IsConfigDir(baseDir, path string) bool {
 	primaryPaths, overridePaths, _ := p.configDirFiles(baseDir, path)
 	return (len(primaryPaths) + len(overridePaths)) > 0
@@ -259,6 +316,18 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 		return
 	}
 
+	// OpenTofu files take precedence over Terraform files with the same name.
+	// e.g. If main.tofu exists, main.tf is ignored.
+	// https://opentofu.org/docs/language/files/#file-extension
+	tofuFiles := map[string]bool{}
+	if p.language != LanguageTerraform {
+		for _, info := range infos {
+			if ext := configFileExt(info.Name()); !info.IsDir() && (ext == ".tofu" || ext == ".tofu.json") {
+				tofuFiles[info.Name()] = true
+			}
+		}
+	}
+
 	for _, info := range infos {
 		if info.IsDir() {
 			// We only care about files
@@ -272,6 +341,23 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 		}
 
 		baseName := name[:len(name)-len(ext)] // strip extension
+		switch ext {
+		case ".tofu", ".tofu.json":
+			if p.language == LanguageTerraform {
+				continue
+			}
+		case ".tf":
+			if tofuFiles[baseName+".tofu"] {
+				log.Printf("[DEBUG] %s is ignored because %s.tofu exists", name, baseName)
+				continue
+			}
+		case ".tf.json":
+			if tofuFiles[baseName+".tofu.json"] {
+				log.Printf("[DEBUG] %s is ignored because %s.tofu.json exists", name, baseName)
+				continue
+			}
+		}
+
 		isOverride := baseName == "override" || strings.HasSuffix(baseName, "_override")
 
 		fullPath := filepath.Join(dir, name)
@@ -285,6 +371,67 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 	return
 }
 
+// testDirFiles returns the test files (.tftest.hcl and .tftest.json) in the given
+// directory and its "tests" subdirectory. Depending on the language, the .tofutest.hcl
+// and .tofutest.json files are also returned, and they take precedence over
+// the Terraform test files with the same name.
+//
+// Unlike configDirFiles, it does not return an error if the directory cannot be read,
+// since test files are optional.
+func (p *Parser) testDirFiles(dir string) []string {
+	var files []string
+
+	for _, testDir := range []string{dir, filepath.Join(dir, defaultTestDir)} {
+		infos, err := p.fs.ReadDir(testDir)
+		if err != nil {
+			continue
+		}
+
+		tofuFiles := map[string]bool{}
+		if p.language != LanguageTerraform {
+			for _, info := range infos {
+				if ext := testFileExt(info.Name()); !info.IsDir() && (ext == ".tofutest.hcl" || ext == ".tofutest.json") {
+					tofuFiles[info.Name()] = true
+				}
+			}
+		}
+
+		for _, info := range infos {
+			if info.IsDir() {
+				continue
+			}
+
+			name := info.Name()
+			ext := testFileExt(name)
+			if ext == "" || isIgnoredFile(name) {
+				continue
+			}
+
+			baseName := name[:len(name)-len(ext)]
+			switch ext {
+			case ".tofutest.hcl", ".tofutest.json":
+				if p.language == LanguageTerraform {
+					continue
+				}
+			case ".tftest.hcl":
+				if tofuFiles[baseName+".tofutest.hcl"] {
+					log.Printf("[DEBUG] %s is ignored because %s.tofutest.hcl exists", name, baseName)
+					continue
+				}
+			case ".tftest.json":
+				if tofuFiles[baseName+".tofutest.json"] {
+					log.Printf("[DEBUG] %s is ignored because %s.tofutest.json exists", name, baseName)
+					continue
+				}
+			}
+
+			files = append(files, filepath.Join(testDir, name))
+		}
+	}
+
+	return files
+}
+
 func (p *Parser) autoLoadValuesDirFiles(baseDir, dir string) (files []string, diags hcl.Diagnostics) {
 	infos, err := p.fs.ReadDir(dir)
 	if err != nil {
@@ -324,11 +471,26 @@ func configFileExt(path string) string {
 		return ".tf"
 	} else if strings.HasSuffix(path, ".tf.json") {
 		return ".tf.json"
+	} else if strings.HasSuffix(path, ".tofu") {
+		return ".tofu"
+	} else if strings.HasSuffix(path, ".tofu.json") {
+		return ".tofu.json"
 	} else {
 		return ""
 	}
 }
 
+// testFileExt returns the test file extension of the given path,
+// or a blank string if it is not a recognized extension.
+func testFileExt(path string) string {
+	for _, ext := range []string{".tftest.hcl", ".tftest.json", ".tofutest.hcl", ".tofutest.json"} {
+		if strings.HasSuffix(path, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
 // isAutoVarFile determines if the file ends with .auto.tfvars or .auto.tfvars.json
 func isAutoVarFile(path string) bool {
 	return strings.HasSuffix(path, ".auto.tfvars") ||
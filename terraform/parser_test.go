@@ -245,13 +245,116 @@ func TestLoadConfigDir(t *testing.T) {
 	}
 }
 
+func TestLoadTestFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language Language
+		want     map[string][]string
+	}{
+		{
+			name: "test files",
+			files: map[string]string{
+				"main.tf": "",
+				"main.tftest.hcl": `
+run "foo" {}
+run "bar" {}`,
+				filepath.Join("tests", "main.tftest.hcl"):  `run "baz" {}`,
+				filepath.Join("tests", "main.tftest.json"): `{"run": {"qux": {}}}`,
+				filepath.Join("tests", "main.tf"):          "",
+			},
+			want: map[string][]string{
+				"main.tftest.hcl":                          {"foo", "bar"},
+				filepath.Join("tests", "main.tftest.hcl"):  {"baz"},
+				filepath.Join("tests", "main.tftest.json"): {"qux"},
+			},
+		},
+		{
+			name: "OpenTofu test files",
+			files: map[string]string{
+				"main.tf":           "",
+				"main.tftest.hcl":   `run "foo" {}`,
+				"main.tofutest.hcl": `run "bar" {}`,
+				"other.tftest.hcl":  `run "baz" {}`,
+			},
+			want: map[string][]string{
+				"main.tofutest.hcl": {"bar"},
+				"other.tftest.hcl":  {"baz"},
+			},
+		},
+		{
+			name: "OpenTofu test files in Terraform language",
+			files: map[string]string{
+				"main.tf":           "",
+				"main.tftest.hcl":   `run "foo" {}`,
+				"main.tofutest.hcl": `run "bar" {}`,
+			},
+			language: LanguageTerraform,
+			want: map[string][]string{
+				"main.tftest.hcl": {"foo"},
+			},
+		},
+		{
+			name: "no test files",
+			files: map[string]string{
+				"main.tf": "",
+			},
+			want: map[string][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			for name, content := range test.files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
+
+			mod, diags := parser.LoadConfigDir(".", ".")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			if len(mod.Tests) > 0 {
+				t.Fatal("test files should not be loaded with the module")
+			}
+			if diags := parser.LoadTestFiles(".", mod); diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got := map[string][]string{}
+			for path, file := range mod.Tests {
+				runs := []string{}
+				for _, run := range file.Runs {
+					runs = append(runs, run.Name)
+				}
+				got[path] = runs
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+
+			// Test files are not part of the module configuration
+			for path := range mod.Tests {
+				if _, exists := mod.Files[path]; exists {
+					t.Errorf("%s should not be included in Files", path)
+				}
+			}
+		})
+	}
+}
+
 func TestLoadConfigDirFiles(t *testing.T) {
 	tests := []struct {
-		name    string
-		files   map[string]string
-		baseDir string
-		dir     string
-		want    []string
+		name     string
+		files    map[string]string
+		language Language
+		baseDir  string
+		dir      string
+		want     []string
 	}{
 		{
 			name: "HCL native files",
@@ -328,6 +431,54 @@ func TestLoadConfigDirFiles(t *testing.T) {
 				filepath.Join("foo", "bar", "override.tf"),
 			},
 		},
+		{
+			name: "OpenTofu files",
+			files: map[string]string{
+				"main.tofu":          "",
+				"main.tf":            "",
+				"variables.tf":       "",
+				"outputs.tofu.json":  "{}",
+				"outputs.tf.json":    "{}",
+				"main_override.tofu": "",
+				"main_override.tf":   "",
+			},
+			baseDir: ".",
+			dir:     ".",
+			want: []string{
+				"main.tofu",
+				"variables.tf",
+				"outputs.tofu.json",
+				"main_override.tofu",
+			},
+		},
+		{
+			name: "OpenTofu files in Terraform language",
+			files: map[string]string{
+				"main.tofu":         "",
+				"main.tf":           "",
+				"outputs.tofu.json": "{}",
+				"outputs.tf.json":   "{}",
+			},
+			language: LanguageTerraform,
+			baseDir:  ".",
+			dir:      ".",
+			want: []string{
+				"main.tf",
+				"outputs.tf.json",
+			},
+		},
+		{
+			name: "Terraform files in OpenTofu language",
+			files: map[string]string{
+				"main.tf": "",
+			},
+			language: LanguageOpenTofu,
+			baseDir:  ".",
+			dir:      ".",
+			want: []string{
+				"main.tf",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -339,6 +490,7 @@ func TestLoadConfigDirFiles(t *testing.T) {
 				}
 			}
 			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
 
 			files, diags := parser.LoadConfigDirFiles(test.baseDir, test.dir)
 			if diags.HasErrors() {
@@ -3,21 +3,104 @@ package terraform
 import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
+	"github.com/nholuongut/tflint/terraform/addrs"
 )
 
 type Resource struct {
+	Mode addrs.ResourceMode
 	Name string
 	Type string
 
+	Count   hcl.Expression
+	ForEach hcl.Expression
+
+	Preconditions  []*CheckRule
+	Postconditions []*CheckRule
+
 	DeclRange hcl.Range
 	TypeRange hcl.Range
 }
 
 func decodeResourceBlock(block *hclext.Block) *Resource {
-	return &Resource{
+	r := &Resource{
+		Mode:      addrs.ManagedResourceMode,
 		Type:      block.Labels[0],
 		Name:      block.Labels[1],
 		DeclRange: block.DefRange,
 		TypeRange: block.LabelRanges[0],
 	}
+
+	if attr, exists := block.Body.Attributes["count"]; exists {
+		r.Count = attr.Expr
+	}
+
+	if attr, exists := block.Body.Attributes["for_each"]; exists {
+		r.ForEach = attr.Expr
+	}
+
+	for _, lifecycle := range block.Body.Blocks {
+		if lifecycle.Type != "lifecycle" {
+			continue
+		}
+		for _, b := range lifecycle.Body.Blocks {
+			switch b.Type {
+			case "precondition":
+				r.Preconditions = append(r.Preconditions, decodeCheckRuleBlock(b))
+			case "postcondition":
+				r.Postconditions = append(r.Postconditions, decodeCheckRuleBlock(b))
+			}
+		}
+	}
+
+	return r
+}
+
+func decodeDataBlock(block *hclext.Block) *Resource {
+	r := decodeResourceBlock(block)
+	r.Mode = addrs.DataResourceMode
+	return r
+}
+
+func decodeEphemeralBlock(block *hclext.Block) *Resource {
+	r := decodeResourceBlock(block)
+	r.Mode = addrs.EphemeralResourceMode
+	return r
+}
+
+var resourceBlockSchema = &hclext.BodySchema{
+	Attributes: []hclext.AttributeSchema{
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+	},
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "lifecycle",
+			Body: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type: "precondition",
+						Body: checkRuleBlockSchema,
+					},
+					{
+						Type: "postcondition",
+						Body: checkRuleBlockSchema,
+					},
+				},
+			},
+		},
+	},
+}
+
+// resourceMetaArguments are arguments that are not resource attributes.
+// These are never resolved as values of the resource.
+var resourceMetaArguments = map[string]bool{
+	"count":      true,
+	"for_each":   true,
+	"provider":   true,
+	"depends_on": true,
+	"lifecycle":  true,
 }
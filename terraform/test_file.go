@@ -0,0 +1,243 @@
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/nholuongut/tflint-plugin-sdk/hclext"
+	"github.com/nholuongut/tflint/terraform/lang"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TestFile represents a Terraform test file (*.tftest.hcl).
+// https://developer.hashicorp.com/terraform/language/tests
+type TestFile struct {
+	// Variables are the global variables shared by all run blocks in the file.
+	Variables hclext.Attributes
+
+	Runs []*TestRun
+
+	Filename string
+}
+
+// TestRun represents a "run" block in a test file.
+type TestRun struct {
+	Name string
+
+	// Command is either "apply" or "plan". The default is "apply".
+	Command string
+
+	// Variables are the variables set only for this run block.
+	// These take precedence over the global variables in the file.
+	Variables hclext.Attributes
+
+	// Module is the alternate module under test.
+	// This is nil if the run block tests the main configuration.
+	Module *TestRunModule
+
+	Asserts        []*CheckRule
+	ExpectFailures []hcl.Traversal
+
+	NameRange hcl.Range
+	DeclRange hcl.Range
+}
+
+// TestRunModule represents a "module" block in a run block.
+type TestRunModule struct {
+	Source      string
+	SourceRange hcl.Range
+
+	DeclRange hcl.Range
+}
+
+func decodeTestFile(filename string, body hcl.Body) (*TestFile, hcl.Diagnostics) {
+	tf := &TestFile{
+		Variables: hclext.Attributes{},
+		Filename:  filename,
+	}
+
+	content, diags := hclext.PartialContent(body, testFileSchema)
+	if diags.HasErrors() {
+		return tf, diags
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			for name, attr := range block.Body.Attributes {
+				tf.Variables[name] = attr
+			}
+		case "run":
+			run, runDiags := decodeTestRunBlock(block)
+			diags = diags.Extend(runDiags)
+			tf.Runs = append(tf.Runs, run)
+		}
+	}
+
+	return tf, diags
+}
+
+func decodeTestRunBlock(block *hclext.Block) (*TestRun, hcl.Diagnostics) {
+	r := &TestRun{
+		Name:      block.Labels[0],
+		Command:   "apply",
+		Variables: hclext.Attributes{},
+		NameRange: block.LabelRanges[0],
+		DeclRange: block.DefRange,
+	}
+	diags := hcl.Diagnostics{}
+
+	if attr, exists := block.Body.Attributes["command"]; exists {
+		switch hcl.ExprAsKeyword(attr.Expr) {
+		case "apply":
+			r.Command = "apply"
+		case "plan":
+			r.Command = "plan"
+		default:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid command keyword",
+				Detail:   "The command argument requires one of the following keywords without quotes: apply or plan.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if attr, exists := block.Body.Attributes["expect_failures"]; exists {
+		exprs, exprDiags := hcl.ExprList(attr.Expr)
+		diags = diags.Extend(exprDiags)
+		for _, expr := range exprs {
+			traversal, travDiags := hcl.AbsTraversalForExpr(expr)
+			diags = diags.Extend(travDiags)
+			if !travDiags.HasErrors() {
+				r.ExpectFailures = append(r.ExpectFailures, traversal)
+			}
+		}
+	}
+
+	for _, b := range block.Body.Blocks {
+		switch b.Type {
+		case "variables":
+			for name, attr := range b.Body.Attributes {
+				r.Variables[name] = attr
+			}
+		case "module":
+			r.Module = &TestRunModule{DeclRange: b.DefRange}
+			if attr, exists := b.Body.Attributes["source"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Module.Source)
+				diags = diags.Extend(valDiags)
+				r.Module.SourceRange = attr.Expr.Range()
+			}
+		case "assert":
+			r.Asserts = append(r.Asserts, decodeCheckRuleBlock(b))
+		}
+	}
+
+	return r, diags
+}
+
+// TestRunValues evaluates the variables set for the given run block. Variables set
+// in the run block take precedence over the global variables in the test file.
+//
+// Since outputs of other run blocks are not available in static analysis,
+// references to `run.*` are evaluated as unknown values. Global variables in
+// the file can be referenced as `var.*`.
+//
+// The values are not checked against the variable declarations of the module
+// under test. Use CheckUndeclaredValues and CheckValueTypes to check them.
+// Variables whose expressions cannot be evaluated are returned as diagnostics.
+func TestRunValues(file *TestFile, run *TestRun) (InputValues, hcl.Diagnostics) {
+	ret := InputValues{}
+	diags := hcl.Diagnostics{}
+
+	attrs := hclext.Attributes{}
+	for name, attr := range file.Variables {
+		attrs[name] = attr
+	}
+	for name, attr := range run.Variables {
+		attrs[name] = attr
+	}
+
+	// Global variables are evaluated first since run variables can refer to them.
+	globals := map[string]cty.Value{}
+	for name, attr := range file.Variables {
+		val, valDiags := attr.Expr.Value(testEvalContext(nil))
+		if valDiags.HasErrors() {
+			val = cty.DynamicVal
+		}
+		globals[name] = val
+	}
+	ctx := testEvalContext(globals)
+
+	for name, attr := range attrs {
+		val, valDiags := attr.Expr.Value(ctx)
+		diags = diags.Extend(valDiags)
+		if valDiags.HasErrors() {
+			continue
+		}
+		ret[name] = &InputValue{
+			Value:       val,
+			SourceType:  ValueFromTestFile,
+			SourceRange: attr.Expr.Range(),
+		}
+	}
+
+	return ret, diags
+}
+
+func testEvalContext(vars map[string]cty.Value) *hcl.EvalContext {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"run": cty.DynamicVal,
+		},
+		Functions: (&lang.Scope{}).Functions(),
+	}
+	if vars != nil {
+		ctx.Variables["var"] = cty.ObjectVal(vars)
+	}
+	return ctx
+}
+
+var testFileSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "variables",
+			Body: &hclext.BodySchema{Mode: hclext.SchemaJustAttributesMode},
+		},
+		{
+			Type:       "run",
+			LabelNames: []string{"name"},
+			Body:       testRunBlockSchema,
+		},
+	},
+}
+
+var testRunBlockSchema = &hclext.BodySchema{
+	Attributes: []hclext.AttributeSchema{
+		{
+			Name: "command",
+		},
+		{
+			Name: "expect_failures",
+		},
+	},
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "variables",
+			Body: &hclext.BodySchema{Mode: hclext.SchemaJustAttributesMode},
+		},
+		{
+			Type: "module",
+			Body: &hclext.BodySchema{
+				Attributes: []hclext.AttributeSchema{
+					{
+						Name: "source",
+					},
+				},
+			},
+		},
+		{
+			Type: "assert",
+			Body: checkRuleBlockSchema,
+		},
+	},
+}
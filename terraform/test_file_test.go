@@ -0,0 +1,203 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecodeTestFile(t *testing.T) {
+	src := `
+variables {
+  foo = "global"
+}
+
+run "setup" {
+  command = plan
+
+  module {
+    source = "./testing/setup"
+  }
+}
+
+run "main" {
+  variables {
+    bar = 1
+  }
+
+  assert {
+    condition     = output.foo == "global"
+    error_message = "invalid"
+  }
+
+  expect_failures = [var.bar]
+}`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	got, diags := decodeTestFile("main.tftest.hcl", file.Body)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if _, exists := got.Variables["foo"]; !exists {
+		t.Errorf("global variable `foo` is not found")
+	}
+	if len(got.Runs) != 2 {
+		t.Fatalf("want 2 runs, got %d", len(got.Runs))
+	}
+
+	setup := got.Runs[0]
+	if setup.Name != "setup" || setup.Command != "plan" {
+		t.Errorf("unexpected run: name=%s, command=%s", setup.Name, setup.Command)
+	}
+	if setup.Module == nil || setup.Module.Source != "./testing/setup" {
+		t.Errorf("unexpected module: %#v", setup.Module)
+	}
+
+	main := got.Runs[1]
+	if main.Name != "main" || main.Command != "apply" {
+		t.Errorf("unexpected run: name=%s, command=%s", main.Name, main.Command)
+	}
+	if main.Module != nil {
+		t.Errorf("module should be nil, got %#v", main.Module)
+	}
+	if _, exists := main.Variables["bar"]; !exists {
+		t.Errorf("run variable `bar` is not found")
+	}
+	if len(main.Asserts) != 1 {
+		t.Errorf("want 1 assert, got %d", len(main.Asserts))
+	}
+	if len(main.ExpectFailures) != 1 || main.ExpectFailures[0].RootName() != "var" {
+		t.Errorf("unexpected expect_failures: %#v", main.ExpectFailures)
+	}
+}
+
+func TestDecodeTestFile_invalidCommand(t *testing.T) {
+	file, diags := hclsyntax.ParseConfig([]byte(`run "main" { command = destroy }`), "main.tftest.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	_, diags = decodeTestFile("main.tftest.hcl", file.Body)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error, but got no errors")
+	}
+	if diags[0].Summary != "Invalid command keyword" {
+		t.Errorf("unexpected error: %s", diags[0].Summary)
+	}
+}
+
+func TestTestRunValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		test  string
+		want  map[string]cty.Value
+		diags []string
+	}{
+		{
+			name: "run variables take precedence",
+			test: `
+variables {
+  foo = "global"
+  bar = "global"
+}
+run "main" {
+  variables {
+    bar = "run"
+  }
+}`,
+			want: map[string]cty.Value{
+				"foo": cty.StringVal("global"),
+				"bar": cty.StringVal("run"),
+			},
+		},
+		{
+			name: "reference to global variables and runs",
+			test: `
+variables {
+  foo = "global"
+}
+run "main" {
+  variables {
+    bar = "${var.foo}-run"
+    num = run.setup.num
+  }
+}`,
+			want: map[string]cty.Value{
+				"foo": cty.StringVal("global"),
+				"bar": cty.StringVal("global-run"),
+				"num": cty.DynamicVal,
+			},
+		},
+		{
+			name: "values are not converted",
+			test: `
+run "main" {
+  variables {
+    num = "1"
+  }
+}`,
+			want: map[string]cty.Value{
+				"num": cty.StringVal("1"),
+			},
+		},
+		{
+			name: "invalid expressions",
+			test: `
+run "main" {
+  variables {
+    foo = "foo"
+    bar = local.bar
+  }
+}`,
+			want: map[string]cty.Value{
+				"foo": cty.StringVal("foo"),
+			},
+			diags: []string{"Unknown variable"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(test.test), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			tf, diags := decodeTestFile("main.tftest.hcl", file.Body)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			values, diags := TestRunValues(tf, tf.Runs[0])
+
+			got := map[string]cty.Value{}
+			for name, v := range values {
+				got[name] = v.Value
+				if v.SourceType != ValueFromTestFile {
+					t.Errorf("unexpected source type of %s: %c", name, v.SourceType)
+				}
+			}
+			opt := cmp.Comparer(func(x, y cty.Value) bool {
+				return x.RawEquals(y)
+			})
+			if diff := cmp.Diff(test.want, got, opt); diff != "" {
+				t.Error(diff)
+			}
+
+			summaries := []string{}
+			for _, diag := range diags {
+				summaries = append(summaries, diag.Summary)
+			}
+			if diff := cmp.Diff(test.diags, summaries, cmpopts.EquateEmpty()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -296,7 +296,13 @@ func (b *expandBody) expandMetaArgBlock(schema *hcl.BodySchema, rawBlock *hcl.Bl
 		var blocks hcl.Blocks
 
 		for it := spec.forEachVal.ElementIterator(); it.Next(); {
-			i := MakeForEachIteration(it.Element())
+			key, value := it.Element()
+			// Instances with unknown keys (e.g. unknown elements in a set) are not expanded
+			// because it is impossible to know which instances they are.
+			if !key.IsKnown() {
+				continue
+			}
+			i := MakeForEachIteration(key, value)
 
 			expandedBlock := *rawBlock // shallow copy
 			expandedBlock.Body = b.expandChild(rawBlock.Body, b.dynamicIteration, i)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/nholuongut/tflint/terraform/tfdiags"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
@@ -310,6 +311,14 @@ func (b *expandBody) decodeMetaArgSpec(rawSpec *hcl.Block) (*expandMetaArgSpec,
 		eachVal, eachDiags := eachAttr.Expr.Value(b.ctx)
 		diags = append(diags, eachDiags...)
 
+		// A "for" expression is entirely unknown if any of the keys or conditions
+		// is unknown. Try to recover instances whose keys are known.
+		if !eachDiags.HasErrors() && !eachVal.IsKnown() {
+			if partial, ok := partialForEachValue(eachAttr.Expr, b.ctx); ok {
+				eachVal = partial
+			}
+		}
+
 		spec.forEachVal = eachVal
 
 		if !eachVal.CanIterateElements() && eachVal.Type() != cty.DynamicPseudoType {
@@ -341,3 +350,80 @@ func (b *expandBody) decodeMetaArgSpec(rawSpec *hcl.Block) (*expandMetaArgSpec,
 
 	return spec, diags
 }
+
+// partialForEachValue builds a for_each value from a "for" expression whose result is unknown.
+//
+// HCL returns an unknown value for the whole "for" expression if any of the keys
+// or conditions is unknown, even if most of the keys are known. This evaluates
+// each element of the collection individually and returns an object of the
+// elements whose keys are known and whose conditions are known to be true.
+// The values of the elements can be unknown.
+//
+// Returns false if the expression is not a "for" expression that produces an object,
+// or if the collection itself is unknown.
+//
+// This is only used for the for_each meta-argument. Dynamic blocks are not partially
+// expanded, because blocks whose elements are skipped would be silently dropped from
+// the body and could cause false positives, for example for required blocks.
+func partialForEachValue(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, bool) {
+	forExpr, ok := expr.(*hclsyntax.ForExpr)
+	if !ok || forExpr.KeyExpr == nil || forExpr.Group {
+		return cty.NilVal, false
+	}
+
+	collVal, diags := forExpr.CollExpr.Value(ctx)
+	if diags.HasErrors() || !collVal.IsKnown() || collVal.IsNull() || collVal.IsMarked() || !collVal.CanIterateElements() {
+		return cty.NilVal, false
+	}
+
+	elems := map[string]cty.Value{}
+	for it := collVal.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+
+		childCtx := ctx.NewChild()
+		childCtx.Variables = map[string]cty.Value{forExpr.ValVar: v}
+		if forExpr.KeyVar != "" {
+			childCtx.Variables[forExpr.KeyVar] = k
+		}
+
+		if forExpr.CondExpr != nil {
+			condVal, diags := forExpr.CondExpr.Value(childCtx)
+			if diags.HasErrors() {
+				return cty.NilVal, false
+			}
+			condVal, err := convert.Convert(condVal, cty.Bool)
+			if err != nil || condVal.IsNull() || condVal.IsMarked() {
+				return cty.NilVal, false
+			}
+			// Elements that may not be included are skipped
+			if !condVal.IsKnown() || condVal.False() {
+				continue
+			}
+		}
+
+		keyVal, diags := forExpr.KeyExpr.Value(childCtx)
+		if diags.HasErrors() {
+			return cty.NilVal, false
+		}
+		keyVal, err := convert.Convert(keyVal, cty.String)
+		if err != nil || keyVal.IsNull() || keyVal.IsMarked() {
+			return cty.NilVal, false
+		}
+		if !keyVal.IsKnown() {
+			continue
+		}
+
+		val, diags := forExpr.ValExpr.Value(childCtx)
+		if diags.HasErrors() {
+			val = cty.DynamicVal
+		}
+
+		if _, exists := elems[keyVal.AsString()]; exists {
+			// Duplicate keys are an error in a "for" expression without grouping
+			return cty.NilVal, false
+		}
+		elems[keyVal.AsString()] = val
+	}
+
+	return cty.ObjectVal(elems), true
+}
@@ -24,7 +24,10 @@ type Variable struct {
 
 	ParsingMode VariableParsingMode
 	Sensitive   bool
+	Ephemeral   bool
 	Nullable    bool
+
+	Validations []*CheckRule
 }
 
 func decodeVairableBlock(block *hclext.Block) (*Variable, hcl.Diagnostics) {
@@ -51,6 +54,11 @@ func decodeVairableBlock(block *hclext.Block) (*Variable, hcl.Diagnostics) {
 		diags = diags.Extend(valDiags)
 	}
 
+	if attr, exists := block.Body.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Ephemeral)
+		diags = diags.Extend(valDiags)
+	}
+
 	if attr, exists := block.Body.Attributes["nullable"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Nullable)
 		diags = append(diags, valDiags...)
@@ -88,6 +96,12 @@ func decodeVairableBlock(block *hclext.Block) (*Variable, hcl.Diagnostics) {
 		v.Default = val
 	}
 
+	for _, b := range block.Body.Blocks {
+		if b.Type == "validation" {
+			v.Validations = append(v.Validations, decodeCheckRuleBlock(b))
+		}
+	}
+
 	return v, diags
 }
 
@@ -229,8 +243,17 @@ var variableBlockSchema = &hclext.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 		{
 			Name: "nullable",
 		},
 	},
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "validation",
+			Body: checkRuleBlockSchema,
+		},
+	},
 }
@@ -1,10 +1,13 @@
 package tflint
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -14,30 +17,91 @@ import (
 type Annotation interface {
 	IsAffected(*Issue) bool
 	String() string
+	// Rules returns the rule names the annotation ignores.
+	Rules() []string
+	// Range returns the range of the annotation comment.
+	Range() hcl.Range
+	// Justification returns the reason and the expiry date written after "--".
+	// The expiry date is zero if not specified.
+	Justification() (string, time.Time)
 }
 
 // Annotations is a slice of Annotation
 type Annotations []Annotation
 
 // NewAnnotations find annotations from the passed tokens and return that list.
+// In JSON files, annotations are written in "//" properties instead of comments.
 func NewAnnotations(path string, file *hcl.File) (Annotations, hcl.Diagnostics) {
-	ret := Annotations{}
+	if strings.HasSuffix(path, ".json") {
+		comments, diags := jsonAnnotationComments(path, file.Bytes)
+		if diags.HasErrors() {
+			return Annotations{}, diags
+		}
+		return parseAnnotationComments(comments)
+	}
 
 	tokens, diags := hclsyntax.LexConfig(file.Bytes, path, hcl.Pos{Byte: 0, Line: 1, Column: 1})
 	if diags.HasErrors() {
-		return ret, diags
+		return Annotations{}, diags
+	}
+
+	var blocks hclsyntax.Blocks
+	if body, ok := file.Body.(*hclsyntax.Body); ok {
+		blocks = flattenBlocks(body.Blocks)
 	}
 
+	comments := []annotationComment{}
 	for _, token := range tokens {
 		if token.Type != hclsyntax.TokenComment {
 			continue
 		}
 
+		comment := annotationComment{
+			token:     token,
+			topOfFile: token.Range.Start.Line == 1 && token.Range.Start.Column == 1,
+		}
+		if block := findAnnotatedBlock(blocks, token.Range.Start.Line); block != nil {
+			rng := block.Range()
+			comment.block = &rng
+		}
+		comments = append(comments, comment)
+	}
+
+	ret, parseDiags := parseAnnotationComments(comments)
+	return ret, diags.Extend(parseDiags)
+}
+
+// annotationComment is a comment that may contain an annotation.
+type annotationComment struct {
+	token hclsyntax.Token
+	// topOfFile is true if tflint-ignore-file annotation can be written in the comment
+	topOfFile bool
+	// block is the range of the block that tflint-ignore-block annotation applies to
+	block *hcl.Range
+}
+
+// parseAnnotationComments finds annotations from the passed comments in source order.
+func parseAnnotationComments(comments []annotationComment) (Annotations, hcl.Diagnostics) {
+	ret := Annotations{}
+	diags := hcl.Diagnostics{}
+	// tflint-disable annotations that are not closed by tflint-enable yet
+	opened := []*RangeAnnotation{}
+
+	for _, comment := range comments {
+		token := comment.token
+
 		// tflint-ignore annotation
 		match := lineAnnotationPattern.FindStringSubmatch(string(token.Bytes))
 		if len(match) == 2 {
+			content, reason, expiry, diag := parseAnnotationContent(match[1], token)
+			if diag != nil {
+				diags = append(diags, diag)
+				continue
+			}
 			ret = append(ret, &LineAnnotation{
-				Content: strings.TrimSpace(match[1]),
+				Content: content,
+				Reason:  reason,
+				Expiry:  expiry,
 				Token:   token,
 			})
 			continue
@@ -46,7 +110,7 @@ func NewAnnotations(path string, file *hcl.File) (Annotations, hcl.Diagnostics)
 		// tflint-ignore-file annotation
 		match = fileAnnotationPattern.FindStringSubmatch(string(token.Bytes))
 		if len(match) == 2 {
-			if !(token.Range.Start.Line == 1 && token.Range.Start.Column == 1) {
+			if !comment.topOfFile {
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "tflint-ignore-file annotation must be written at the top of file",
@@ -55,22 +119,109 @@ func NewAnnotations(path string, file *hcl.File) (Annotations, hcl.Diagnostics)
 				})
 				continue
 			}
+			content, reason, expiry, diag := parseAnnotationContent(match[1], token)
+			if diag != nil {
+				diags = append(diags, diag)
+				continue
+			}
 			ret = append(ret, &FileAnnotation{
-				Content: strings.TrimSpace(match[1]),
+				Content: content,
+				Reason:  reason,
+				Expiry:  expiry,
 				Token:   token,
 			})
 			continue
 		}
+
+		// tflint-ignore-block annotation
+		match = blockAnnotationPattern.FindStringSubmatch(string(token.Bytes))
+		if len(match) == 2 {
+			if comment.block == nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "tflint-ignore-block annotation must be written before a block",
+					Detail:   fmt.Sprintf("No block starts at line %d or %d", token.Range.Start.Line, token.Range.Start.Line+1),
+					Subject:  token.Range.Ptr(),
+				})
+				continue
+			}
+			content, reason, expiry, diag := parseAnnotationContent(match[1], token)
+			if diag != nil {
+				diags = append(diags, diag)
+				continue
+			}
+			ret = append(ret, &BlockAnnotation{
+				Content: content,
+				Reason:  reason,
+				Expiry:  expiry,
+				Token:   token,
+				Block:   *comment.block,
+			})
+			continue
+		}
+
+		// tflint-disable annotation
+		match = disableAnnotationPattern.FindStringSubmatch(string(token.Bytes))
+		if len(match) == 2 {
+			content, reason, expiry, diag := parseAnnotationContent(match[1], token)
+			if diag != nil {
+				diags = append(diags, diag)
+				continue
+			}
+			annotation := &RangeAnnotation{
+				Content: content,
+				Reason:  reason,
+				Expiry:  expiry,
+				Token:   token,
+			}
+			ret = append(ret, annotation)
+			opened = append(opened, annotation)
+			continue
+		}
+
+		// tflint-enable annotation
+		match = enableAnnotationPattern.FindStringSubmatch(string(token.Bytes))
+		if len(match) == 2 {
+			// Close the most recently opened region for the same rules.
+			// The reason is not required for tflint-enable, so it is ignored.
+			content, _, _, _ := parseAnnotationContent(match[1], token)
+			rules := annotationRules(content)
+			slices.Sort(rules)
+			idx := -1
+			for i := len(opened) - 1; i >= 0; i-- {
+				openedRules := annotationRules(opened[i].Content)
+				slices.Sort(openedRules)
+				if slices.Equal(openedRules, rules) {
+					idx = i
+					break
+				}
+			}
+			if idx == -1 {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "tflint-enable annotation must be preceded by a tflint-disable annotation for the same rules",
+					Detail:   fmt.Sprintf("No tflint-disable annotation for %q is open at line %d", strings.Join(rules, ", "), token.Range.Start.Line),
+					Subject:  token.Range.Ptr(),
+				})
+				continue
+			}
+			end := token
+			opened[idx].EndToken = &end
+			opened = slices.Delete(opened, idx, idx+1)
+			continue
+		}
 	}
 
 	return ret, diags
 }
 
-var lineAnnotationPattern = regexp.MustCompile(`tflint-ignore: ([^\n*/#]+)`)
+var lineAnnotationPattern = regexp.MustCompile(`tflint-ignore: ([^\n]+)`)
 
 // LineAnnotation is an annotation for ignoring issues in a line
 type LineAnnotation struct {
 	Content string
+	Reason  string
+	Expiry  time.Time
 	Token   hclsyntax.Token
 }
 
@@ -79,13 +230,11 @@ func (a *LineAnnotation) IsAffected(issue *Issue) bool {
 	if a.Token.Range.Filename != issue.Range.Filename {
 		return false
 	}
-
-	rules := strings.Split(a.Content, ",")
-	for i, rule := range rules {
-		rules[i] = strings.TrimSpace(rule)
+	if annotationExpired(a.Expiry) {
+		return false
 	}
 
-	if slices.Contains(rules, issue.Rule.Name()) || slices.Contains(rules, "all") {
+	if matchAnnotationRules(a.Content, issue) {
 		if a.Token.Range.Start.Line == issue.Range.Start.Line {
 			return true
 		}
@@ -101,11 +250,28 @@ func (a *LineAnnotation) String() string {
 	return fmt.Sprintf("tflint-ignore: %s (%s)", a.Content, a.Token.Range.String())
 }
 
-var fileAnnotationPattern = regexp.MustCompile(`tflint-ignore-file: ([^\n*/#]+)`)
+// Rules returns the rule names the annotation ignores
+func (a *LineAnnotation) Rules() []string {
+	return annotationRules(a.Content)
+}
+
+// Range returns the range of the annotation comment
+func (a *LineAnnotation) Range() hcl.Range {
+	return a.Token.Range
+}
+
+// Justification returns the reason and the expiry date of the annotation
+func (a *LineAnnotation) Justification() (string, time.Time) {
+	return a.Reason, a.Expiry
+}
+
+var fileAnnotationPattern = regexp.MustCompile(`tflint-ignore-file: ([^\n]+)`)
 
 // FileAnnotation is an annotation for ignoring issues in a file
 type FileAnnotation struct {
 	Content string
+	Reason  string
+	Expiry  time.Time
 	Token   hclsyntax.Token
 }
 
@@ -114,19 +280,231 @@ func (a *FileAnnotation) IsAffected(issue *Issue) bool {
 	if a.Token.Range.Filename != issue.Range.Filename {
 		return false
 	}
+	if annotationExpired(a.Expiry) {
+		return false
+	}
 
-	rules := strings.Split(a.Content, ",")
-	for i, rule := range rules {
-		rules[i] = strings.TrimSpace(rule)
+	return matchAnnotationRules(a.Content, issue)
+}
+
+// String returns the string representation of the annotation
+func (a *FileAnnotation) String() string {
+	return fmt.Sprintf("tflint-ignore-file: %s (%s)", a.Content, a.Token.Range.String())
+}
+
+// Rules returns the rule names the annotation ignores
+func (a *FileAnnotation) Rules() []string {
+	return annotationRules(a.Content)
+}
+
+// Range returns the range of the annotation comment
+func (a *FileAnnotation) Range() hcl.Range {
+	return a.Token.Range
+}
+
+// Justification returns the reason and the expiry date of the annotation
+func (a *FileAnnotation) Justification() (string, time.Time) {
+	return a.Reason, a.Expiry
+}
+
+var blockAnnotationPattern = regexp.MustCompile(`tflint-ignore-block: ([^\n]+)`)
+
+// BlockAnnotation is an annotation for ignoring issues in a block
+type BlockAnnotation struct {
+	Content string
+	Reason  string
+	Expiry  time.Time
+	Token   hclsyntax.Token
+	Block   hcl.Range
+}
+
+// IsAffected checks if the passed issue is affected with the annotation
+func (a *BlockAnnotation) IsAffected(issue *Issue) bool {
+	if a.Token.Range.Filename != issue.Range.Filename {
+		return false
+	}
+	if annotationExpired(a.Expiry) {
+		return false
 	}
 
-	if slices.Contains(rules, issue.Rule.Name()) || slices.Contains(rules, "all") {
-		return true
+	if matchAnnotationRules(a.Content, issue) {
+		return a.Block.Start.Line <= issue.Range.Start.Line && issue.Range.Start.Line <= a.Block.End.Line
 	}
 	return false
 }
 
 // String returns the string representation of the annotation
-func (a *FileAnnotation) String() string {
-	return fmt.Sprintf("tflint-ignore-file: %s (%s)", a.Content, a.Token.Range.String())
+func (a *BlockAnnotation) String() string {
+	return fmt.Sprintf("tflint-ignore-block: %s (%s)", a.Content, a.Token.Range.String())
+}
+
+// Rules returns the rule names the annotation ignores
+func (a *BlockAnnotation) Rules() []string {
+	return annotationRules(a.Content)
+}
+
+// Range returns the range of the annotation comment
+func (a *BlockAnnotation) Range() hcl.Range {
+	return a.Token.Range
+}
+
+// Justification returns the reason and the expiry date of the annotation
+func (a *BlockAnnotation) Justification() (string, time.Time) {
+	return a.Reason, a.Expiry
+}
+
+var disableAnnotationPattern = regexp.MustCompile(`tflint-disable: ([^\n]+)`)
+var enableAnnotationPattern = regexp.MustCompile(`tflint-enable: ([^\n]+)`)
+
+// RangeAnnotation is an annotation for ignoring issues between tflint-disable and tflint-enable.
+// If EndToken is nil, the range continues to the end of the file.
+type RangeAnnotation struct {
+	Content  string
+	Reason   string
+	Expiry   time.Time
+	Token    hclsyntax.Token
+	EndToken *hclsyntax.Token
+}
+
+// IsAffected checks if the passed issue is affected with the annotation
+func (a *RangeAnnotation) IsAffected(issue *Issue) bool {
+	if a.Token.Range.Filename != issue.Range.Filename {
+		return false
+	}
+	if annotationExpired(a.Expiry) {
+		return false
+	}
+
+	if matchAnnotationRules(a.Content, issue) {
+		if issue.Range.Start.Line < a.Token.Range.Start.Line {
+			return false
+		}
+		return a.EndToken == nil || issue.Range.Start.Line <= a.EndToken.Range.Start.Line
+	}
+	return false
+}
+
+// String returns the string representation of the annotation
+func (a *RangeAnnotation) String() string {
+	return fmt.Sprintf("tflint-disable: %s (%s)", a.Content, a.Token.Range.String())
+}
+
+// Rules returns the rule names the annotation ignores
+func (a *RangeAnnotation) Rules() []string {
+	return annotationRules(a.Content)
+}
+
+// Range returns the range of the annotation comment
+func (a *RangeAnnotation) Range() hcl.Range {
+	return a.Token.Range
+}
+
+// Justification returns the reason and the expiry date of the annotation
+func (a *RangeAnnotation) Justification() (string, time.Time) {
+	return a.Reason, a.Expiry
+}
+
+var annotationExpiryPattern = regexp.MustCompile(`\[until ([^\]]*)\]\s*$`)
+
+// parseAnnotationContent splits the annotation content like "rule_a, rule_b -- reason [until 2006-01-02]"
+// into the rule list, the reason, and the expiry date.
+func parseAnnotationContent(raw string, token hclsyntax.Token) (string, string, time.Time, *hcl.Diagnostic) {
+	// The closing "*/" of a block comment is not a part of the annotation
+	if bytes.HasPrefix(token.Bytes, []byte("/*")) {
+		raw = strings.TrimSuffix(strings.TrimSpace(raw), "*/")
+	}
+
+	content, reason, _ := strings.Cut(raw, "--")
+	// The rule list ends at another comment like "rule_a # comment",
+	// while the reason can contain any characters, e.g. URLs.
+	if i := strings.IndexAny(content, "*/#"); i >= 0 {
+		content = content[:i]
+	}
+	content = strings.TrimSpace(content)
+	reason = strings.TrimSpace(reason)
+
+	var expiry time.Time
+	if match := annotationExpiryPattern.FindStringSubmatchIndex(reason); match != nil {
+		date := reason[match[2]:match[3]]
+		var err error
+		expiry, err = time.Parse(time.DateOnly, strings.TrimSpace(date))
+		if err != nil {
+			return "", "", time.Time{}, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid expiry date in annotation",
+				Detail:   fmt.Sprintf(`"%s" is not a valid date. The expiry date must be in YYYY-MM-DD format`, date),
+				Subject:  token.Range.Ptr(),
+			}
+		}
+		reason = strings.TrimSpace(reason[:match[0]])
+	}
+
+	return content, reason, expiry, nil
+}
+
+// annotationExpired checks if the expiry date has passed.
+// The expiry date is inclusive, so annotations expire at the beginning of the next day in UTC.
+func annotationExpired(expiry time.Time) bool {
+	return !expiry.IsZero() && !time.Now().Before(expiry.AddDate(0, 0, 1))
+}
+
+// annotationUsage records annotations that ignored issues.
+// It is shared between the root module runner and module runners,
+// which may emit issues concurrently.
+type annotationUsage struct {
+	mu   sync.Mutex
+	used map[Annotation]bool
+}
+
+func newAnnotationUsage() *annotationUsage {
+	return &annotationUsage{used: map[Annotation]bool{}}
+}
+
+func (u *annotationUsage) record(annotation Annotation) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.used[annotation] = true
+}
+
+func (u *annotationUsage) isUsed(annotation Annotation) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.used[annotation]
+}
+
+// annotationRules returns the rule names in the annotation content
+func annotationRules(content string) []string {
+	rules := strings.Split(content, ",")
+	for i, rule := range rules {
+		rules[i] = strings.TrimSpace(rule)
+	}
+	return rules
+}
+
+// matchAnnotationRules checks if the annotation content contains the rule of the passed issue
+func matchAnnotationRules(content string, issue *Issue) bool {
+	rules := annotationRules(content)
+	return slices.Contains(rules, issue.Rule.Name()) || slices.Contains(rules, "all")
+}
+
+// flattenBlocks returns the passed blocks and all nested blocks in source order
+func flattenBlocks(blocks hclsyntax.Blocks) hclsyntax.Blocks {
+	ret := hclsyntax.Blocks{}
+	for _, block := range blocks {
+		ret = append(ret, block)
+		ret = append(ret, flattenBlocks(block.Body.Blocks)...)
+	}
+	return ret
+}
+
+// findAnnotatedBlock returns the outermost block whose header is on the same line as
+// the annotation or on the next line
+func findAnnotatedBlock(blocks hclsyntax.Blocks, line int) *hclsyntax.Block {
+	for _, block := range blocks {
+		start := block.Range().Start.Line
+		if start == line || start == line+1 {
+			return block
+		}
+	}
+	return nil
 }
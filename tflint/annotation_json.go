@@ -0,0 +1,172 @@
+package tflint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// jsonCommentProperty is the property name Terraform treats as a comment in JSON syntax.
+const jsonCommentProperty = "//"
+
+// jsonAnnotationComments finds "//" properties in the passed JSON source and returns
+// their values as comments. The value can be a string or an array of strings.
+//
+// Annotations in "//" properties have the same semantics as comments in the native syntax,
+// except that tflint-ignore-block annotation applies to the object containing the property,
+// and tflint-ignore-file annotation must be written in the root object.
+func jsonAnnotationComments(path string, src []byte) ([]annotationComment, hcl.Diagnostics) {
+	s := &jsonAnnotationScanner{
+		path: path,
+		src:  src,
+		dec:  json.NewDecoder(bytes.NewReader(src)),
+	}
+	if err := s.scanValue(0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to find annotations",
+				Detail:   fmt.Sprintf("%s: %s", path, err),
+			},
+		}
+	}
+
+	slices.SortFunc(s.comments, func(a, b annotationComment) int {
+		return a.token.Range.Start.Byte - b.token.Range.Start.Byte
+	})
+	return s.comments, nil
+}
+
+type jsonAnnotationScanner struct {
+	path     string
+	src      []byte
+	dec      *json.Decoder
+	comments []annotationComment
+}
+
+func (s *jsonAnnotationScanner) scanValue(depth int) error {
+	start := s.nextOffset()
+	token, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		comments := []annotationComment{}
+		for s.dec.More() {
+			key, err := s.dec.Token()
+			if err != nil {
+				return err
+			}
+			if key != jsonCommentProperty {
+				if err := s.scanValue(depth + 1); err != nil {
+					return err
+				}
+				continue
+			}
+
+			found, err := s.scanComment()
+			if err != nil {
+				return err
+			}
+			comments = append(comments, found...)
+		}
+		if _, err := s.dec.Token(); err != nil {
+			return err
+		}
+
+		rng := s.rangeOf(start, int(s.dec.InputOffset()))
+		for _, comment := range comments {
+			comment.topOfFile = depth == 0
+			comment.block = &rng
+			s.comments = append(s.comments, comment)
+		}
+
+	case json.Delim('['):
+		for s.dec.More() {
+			if err := s.scanValue(depth + 1); err != nil {
+				return err
+			}
+		}
+		if _, err := s.dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanComment reads the value of "//" property. Values other than strings are ignored.
+func (s *jsonAnnotationScanner) scanComment() ([]annotationComment, error) {
+	start := s.nextOffset()
+	if start < len(s.src) && s.src[start] == '[' {
+		if _, err := s.dec.Token(); err != nil {
+			return nil, err
+		}
+		ret := []annotationComment{}
+		for s.dec.More() {
+			found, err := s.scanComment()
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, found...)
+		}
+		_, err := s.dec.Token()
+		return ret, err
+	}
+
+	var value any
+	if err := s.dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if str, ok := value.(string); ok {
+		return []annotationComment{s.comment(str, start)}, nil
+	}
+	return nil, nil
+}
+
+func (s *jsonAnnotationScanner) comment(value string, start int) annotationComment {
+	return annotationComment{
+		// "//" properties are treated as comments in JSON syntax
+		token: hclsyntax.Token{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte(value),
+			Range: s.rangeOf(start, int(s.dec.InputOffset())),
+		},
+	}
+}
+
+// nextOffset returns the offset of the next token, skipping whitespaces and separators
+// that the decoder has not consumed yet.
+func (s *jsonAnnotationScanner) nextOffset() int {
+	offset := int(s.dec.InputOffset())
+	for offset < len(s.src) && bytes.IndexByte([]byte(" \t\r\n,:"), s.src[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (s *jsonAnnotationScanner) rangeOf(start int, end int) hcl.Range {
+	return hcl.Range{
+		Filename: s.path,
+		Start:    s.pos(start),
+		End:      s.pos(end),
+	}
+}
+
+func (s *jsonAnnotationScanner) pos(offset int) hcl.Pos {
+	lineStart := bytes.LastIndexByte(s.src[:offset], '\n') + 1
+	return hcl.Pos{
+		Line:   bytes.Count(s.src[:offset], []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(s.src[lineStart:offset]) + 1,
+		Byte:   offset,
+	}
+}
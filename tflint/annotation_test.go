@@ -2,10 +2,12 @@ package tflint
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
@@ -186,6 +188,295 @@ resource "aws_instance" "foo" {
 			want:  Annotations{},
 			diags: "resource.tf:1,33-2,1: tflint-ignore-file annotation must be written at the top of file; tflint-ignore-file annotation is written at line 1, column 33",
 		},
+		{
+			name: "annotation with reason and expiry",
+			src: `
+resource "aws_instance" "foo" {
+  # tflint-ignore: aws_instance_invalid_type, other_rule -- new instance type [until 2027-01-01]
+  instance_type = "t10.micro"
+}`,
+			want: Annotations{
+				&LineAnnotation{
+					Content: "aws_instance_invalid_type, other_rule",
+					Reason:  "new instance type",
+					Expiry:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-ignore: aws_instance_invalid_type, other_rule -- new instance type [until 2027-01-01]\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 3, Column: 3},
+							End:      hcl.Pos{Line: 4, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "tflint-ignore-file annotation with reason",
+			src: `# tflint-ignore-file: aws_instance_invalid_type -- generated file
+resource "aws_instance" "foo" {
+  instance_type = "t2.micro"
+}`,
+			want: Annotations{
+				&FileAnnotation{
+					Content: "aws_instance_invalid_type",
+					Reason:  "generated file",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-ignore-file: aws_instance_invalid_type -- generated file\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 1, Column: 1},
+							End:      hcl.Pos{Line: 2, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "annotation with reason containing a URL",
+			src: `
+resource "aws_instance" "foo" {
+  # tflint-ignore: aws_instance_invalid_type -- see https://example.com/issues/1#note [until 2027-01-01]
+  instance_type = "t10.micro"
+}`,
+			want: Annotations{
+				&LineAnnotation{
+					Content: "aws_instance_invalid_type",
+					Reason:  "see https://example.com/issues/1#note",
+					Expiry:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-ignore: aws_instance_invalid_type -- see https://example.com/issues/1#note [until 2027-01-01]\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 3, Column: 3},
+							End:      hcl.Pos{Line: 4, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "block comment annotation with reason containing a URL",
+			src: `
+resource "aws_instance" "foo" {
+  /* tflint-ignore: aws_instance_invalid_type -- see https://example.com/issues/1 */
+  instance_type = "t10.micro"
+}`,
+			want: Annotations{
+				&LineAnnotation{
+					Content: "aws_instance_invalid_type",
+					Reason:  "see https://example.com/issues/1",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("/* tflint-ignore: aws_instance_invalid_type -- see https://example.com/issues/1 */"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 3, Column: 3},
+							End:      hcl.Pos{Line: 3, Column: 85},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "annotation with invalid expiry",
+			src: `
+resource "aws_instance" "foo" {
+  # tflint-ignore: aws_instance_invalid_type -- new instance type [until next year]
+  instance_type = "t10.micro"
+}`,
+			want:  Annotations{},
+			diags: `resource.tf:3,3-4,1: Invalid expiry date in annotation; "next year" is not a valid date. The expiry date must be in YYYY-MM-DD format`,
+		},
+		{
+			name: "tflint-ignore-block annotation",
+			src: `
+# tflint-ignore-block: aws_instance_invalid_type
+resource "aws_instance" "foo" {
+  instance_type = "t2.micro"
+}`,
+			want: Annotations{
+				&BlockAnnotation{
+					Content: "aws_instance_invalid_type",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-ignore-block: aws_instance_invalid_type\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1},
+							End:      hcl.Pos{Line: 3, Column: 1},
+						},
+					},
+					Block: hcl.Range{
+						Filename: "resource.tf",
+						Start:    hcl.Pos{Line: 3, Column: 1},
+						End:      hcl.Pos{Line: 5, Column: 2},
+					},
+				},
+			},
+		},
+		{
+			name: "tflint-ignore-block annotation on the block header",
+			src: `
+resource "aws_instance" "foo" {
+  dynamic "ebs_block_device" { # tflint-ignore-block: aws_instance_invalid_device
+    for_each = var.devices
+  }
+}`,
+			want: Annotations{
+				&BlockAnnotation{
+					Content: "aws_instance_invalid_device",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-ignore-block: aws_instance_invalid_device\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 3, Column: 32},
+							End:      hcl.Pos{Line: 4, Column: 1},
+						},
+					},
+					Block: hcl.Range{
+						Filename: "resource.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 5, Column: 4},
+					},
+				},
+			},
+		},
+		{
+			name: "tflint-ignore-block annotation without block",
+			src: `
+resource "aws_instance" "foo" {
+  # tflint-ignore-block: aws_instance_invalid_type
+  instance_type = "t2.micro"
+}`,
+			want:  Annotations{},
+			diags: "resource.tf:3,3-4,1: tflint-ignore-block annotation must be written before a block; No block starts at line 3 or 4",
+		},
+		{
+			name: "tflint-disable and tflint-enable annotations",
+			src: `
+# tflint-disable: aws_instance_invalid_type
+resource "aws_instance" "foo" {
+  instance_type = "t2.micro"
+}
+# tflint-enable: aws_instance_invalid_type
+
+# tflint-disable: other_rule, all
+`,
+			want: Annotations{
+				&RangeAnnotation{
+					Content: "aws_instance_invalid_type",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-disable: aws_instance_invalid_type\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1},
+							End:      hcl.Pos{Line: 3, Column: 1},
+						},
+					},
+					EndToken: &hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-enable: aws_instance_invalid_type\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 6, Column: 1},
+							End:      hcl.Pos{Line: 7, Column: 1},
+						},
+					},
+				},
+				&RangeAnnotation{
+					Content: "other_rule, all",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-disable: other_rule, all\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 8, Column: 1},
+							End:      hcl.Pos{Line: 9, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "nested tflint-disable annotations",
+			src: `# tflint-disable: rule_a, rule_b
+# tflint-disable: rule_a -- reason
+# tflint-enable: rule_b, rule_a
+# tflint-enable: rule_a -- reason
+`,
+			want: Annotations{
+				&RangeAnnotation{
+					Content: "rule_a, rule_b",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-disable: rule_a, rule_b\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 1, Column: 1},
+							End:      hcl.Pos{Line: 2, Column: 1},
+						},
+					},
+					EndToken: &hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-enable: rule_b, rule_a\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 3, Column: 1},
+							End:      hcl.Pos{Line: 4, Column: 1},
+						},
+					},
+				},
+				&RangeAnnotation{
+					Content: "rule_a",
+					Reason:  "reason",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-disable: rule_a -- reason\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1},
+							End:      hcl.Pos{Line: 3, Column: 1},
+						},
+					},
+					EndToken: &hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-enable: rule_a -- reason\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 4, Column: 1},
+							End:      hcl.Pos{Line: 5, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "tflint-enable annotation without tflint-disable",
+			src: `# tflint-disable: rule_a
+# tflint-enable: rule_b
+`,
+			want: Annotations{
+				&RangeAnnotation{
+					Content: "rule_a",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("# tflint-disable: rule_a\n"),
+						Range: hcl.Range{
+							Filename: "resource.tf",
+							Start:    hcl.Pos{Line: 1, Column: 1},
+							End:      hcl.Pos{Line: 2, Column: 1},
+						},
+					},
+				},
+			},
+			diags: `resource.tf:2,1-3,1: tflint-enable annotation must be preceded by a tflint-disable annotation for the same rules; No tflint-disable annotation for "rule_b" is open at line 2`,
+		},
 	}
 
 	for _, test := range tests {
@@ -210,6 +501,12 @@ resource "aws_instance" "foo" {
 }
 
 func TestLineAnnotation_IsAffected(t *testing.T) {
+	// The expiry date in annotations is parsed as midnight in UTC
+	today, err := time.Parse(time.DateOnly, time.Now().UTC().Format(time.DateOnly))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	issue := &Issue{
 		Rule:    &testRule{},
 		Message: "Test rule",
@@ -336,6 +633,66 @@ func TestLineAnnotation_IsAffected(t *testing.T) {
 			},
 			Expected: true,
 		},
+		{
+			Name: "affected (not expired)",
+			Annotation: &LineAnnotation{
+				Content: "test_rule",
+				Expiry:  time.Now().Add(24 * time.Hour),
+				Token: hclsyntax.Token{
+					Type: hclsyntax.TokenComment,
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2},
+					},
+				},
+			},
+			Expected: true,
+		},
+		{
+			Name: "not affected (expired)",
+			Annotation: &LineAnnotation{
+				Content: "test_rule",
+				Expiry:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Token: hclsyntax.Token{
+					Type: hclsyntax.TokenComment,
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2},
+					},
+				},
+			},
+			Expected: false,
+		},
+		{
+			Name: "affected (expires today)",
+			Annotation: &LineAnnotation{
+				Content: "test_rule",
+				Expiry:  today,
+				Token: hclsyntax.Token{
+					Type: hclsyntax.TokenComment,
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2},
+					},
+				},
+			},
+			Expected: true,
+		},
+		{
+			Name: "not affected (expired yesterday)",
+			Annotation: &LineAnnotation{
+				Content: "test_rule",
+				Expiry:  today.AddDate(0, 0, -1),
+				Token: hclsyntax.Token{
+					Type: hclsyntax.TokenComment,
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2},
+					},
+				},
+			},
+			Expected: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -452,3 +809,280 @@ func TestFileAnnotation_IsAffected(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockAnnotation_IsAffected(t *testing.T) {
+	annotation := func(content string, filename string) *BlockAnnotation {
+		return &BlockAnnotation{
+			Content: content,
+			Token: hclsyntax.Token{
+				Type:  hclsyntax.TokenComment,
+				Range: hcl.Range{Filename: filename, Start: hcl.Pos{Line: 1}},
+			},
+			Block: hcl.Range{Filename: filename, Start: hcl.Pos{Line: 2}, End: hcl.Pos{Line: 10}},
+		}
+	}
+
+	tests := []struct {
+		Name       string
+		Annotation *BlockAnnotation
+		Line       int
+		Expected   bool
+	}{
+		{
+			Name:       "affected (block header)",
+			Annotation: annotation("test_rule", "test.tf"),
+			Line:       2,
+			Expected:   true,
+		},
+		{
+			Name:       "affected (inside block)",
+			Annotation: annotation("other_rule, test_rule", "test.tf"),
+			Line:       5,
+			Expected:   true,
+		},
+		{
+			Name:       "affected (all)",
+			Annotation: annotation("all", "test.tf"),
+			Line:       10,
+			Expected:   true,
+		},
+		{
+			Name:       "not affected (above block)",
+			Annotation: annotation("test_rule", "test.tf"),
+			Line:       1,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (under block)",
+			Annotation: annotation("test_rule", "test.tf"),
+			Line:       11,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (another filename)",
+			Annotation: annotation("test_rule", "test2.tf"),
+			Line:       5,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (another rule)",
+			Annotation: annotation("test_another_rule", "test.tf"),
+			Line:       5,
+			Expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			issue := &Issue{
+				Rule:    &testRule{},
+				Message: "Test rule",
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: test.Line},
+				},
+			}
+
+			got := test.Annotation.IsAffected(issue)
+			if got != test.Expected {
+				t.Fatalf("want=%t, got=%t", test.Expected, got)
+			}
+		})
+	}
+}
+
+func TestRangeAnnotation_IsAffected(t *testing.T) {
+	annotation := func(content string, filename string, end int) *RangeAnnotation {
+		ret := &RangeAnnotation{
+			Content: content,
+			Token: hclsyntax.Token{
+				Type:  hclsyntax.TokenComment,
+				Range: hcl.Range{Filename: filename, Start: hcl.Pos{Line: 2}},
+			},
+		}
+		if end > 0 {
+			ret.EndToken = &hclsyntax.Token{
+				Type:  hclsyntax.TokenComment,
+				Range: hcl.Range{Filename: filename, Start: hcl.Pos{Line: end}},
+			}
+		}
+		return ret
+	}
+
+	tests := []struct {
+		Name       string
+		Annotation *RangeAnnotation
+		Line       int
+		Expected   bool
+	}{
+		{
+			Name:       "affected (disable line)",
+			Annotation: annotation("test_rule", "test.tf", 10),
+			Line:       2,
+			Expected:   true,
+		},
+		{
+			Name:       "affected (enable line)",
+			Annotation: annotation("test_rule", "test.tf", 10),
+			Line:       10,
+			Expected:   true,
+		},
+		{
+			Name:       "affected (multiple rules)",
+			Annotation: annotation("other_rule, test_rule", "test.tf", 10),
+			Line:       5,
+			Expected:   true,
+		},
+		{
+			Name:       "affected (not enabled)",
+			Annotation: annotation("all", "test.tf", 0),
+			Line:       100,
+			Expected:   true,
+		},
+		{
+			Name:       "not affected (above range)",
+			Annotation: annotation("test_rule", "test.tf", 10),
+			Line:       1,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (under range)",
+			Annotation: annotation("test_rule", "test.tf", 10),
+			Line:       11,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (another filename)",
+			Annotation: annotation("test_rule", "test2.tf", 10),
+			Line:       5,
+			Expected:   false,
+		},
+		{
+			Name:       "not affected (another rule)",
+			Annotation: annotation("test_another_rule", "test.tf", 10),
+			Line:       5,
+			Expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			issue := &Issue{
+				Rule:    &testRule{},
+				Message: "Test rule",
+				Range: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: test.Line},
+				},
+			}
+
+			got := test.Annotation.IsAffected(issue)
+			if got != test.Expected {
+				t.Fatalf("want=%t, got=%t", test.Expected, got)
+			}
+		})
+	}
+}
+
+func TestNewAnnotations_json(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		want  Annotations
+		diags string
+	}{
+		{
+			name: "annotations in comment properties",
+			src: `{
+  "//": "tflint-ignore-file: aws_instance_invalid_ami",
+  "resource": {
+    "aws_instance": {
+      "foo": {
+        "//": "tflint-ignore-block: aws_instance_invalid_type -- generated",
+        "instance_type": "t1.2xlarge",
+        "key_name": "tflint-ignore: not_annotation"
+      },
+      "bar": {
+        "//": ["tflint-ignore: aws_instance_invalid_type", "This is also comment"],
+        "instance_type": "t1.2xlarge"
+      }
+    }
+  }
+}`,
+			want: Annotations{
+				&FileAnnotation{
+					Content: "aws_instance_invalid_ami",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("tflint-ignore-file: aws_instance_invalid_ami"),
+						Range: hcl.Range{
+							Filename: "resource.tf.json",
+							Start:    hcl.Pos{Line: 2, Column: 9},
+							End:      hcl.Pos{Line: 2, Column: 55},
+						},
+					},
+				},
+				&BlockAnnotation{
+					Content: "aws_instance_invalid_type",
+					Reason:  "generated",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("tflint-ignore-block: aws_instance_invalid_type -- generated"),
+						Range: hcl.Range{
+							Filename: "resource.tf.json",
+							Start:    hcl.Pos{Line: 6, Column: 15},
+							End:      hcl.Pos{Line: 6, Column: 76},
+						},
+					},
+					Block: hcl.Range{
+						Filename: "resource.tf.json",
+						Start:    hcl.Pos{Line: 5, Column: 14},
+						End:      hcl.Pos{Line: 9, Column: 8},
+					},
+				},
+				&LineAnnotation{
+					Content: "aws_instance_invalid_type",
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Bytes: []byte("tflint-ignore: aws_instance_invalid_type"),
+						Range: hcl.Range{
+							Filename: "resource.tf.json",
+							Start:    hcl.Pos{Line: 11, Column: 16},
+							End:      hcl.Pos{Line: 11, Column: 58},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "tflint-ignore-file annotation outside the root object",
+			src: `{
+  "resource": {
+    "//": "tflint-ignore-file: aws_instance_invalid_type"
+  }
+}`,
+			want:  Annotations{},
+			diags: "resource.tf.json:3,11-58: tflint-ignore-file annotation must be written at the top of file; tflint-ignore-file annotation is written at line 3, column 11",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file, diags := hclparse.NewParser().ParseJSON([]byte(test.src), "resource.tf.json")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			got, diags := NewAnnotations("resource.tf.json", file)
+			if diags.HasErrors() || test.diags != "" {
+				if diags.Error() != test.diags {
+					t.Errorf("want=%s, got=%s", test.diags, diags.Error())
+				}
+			}
+
+			opts := cmpopts.IgnoreFields(hcl.Pos{}, "Byte")
+			if diff := cmp.Diff(test.want, got, opts); diff != "" {
+				t.Errorf("diff: %s", diff)
+			}
+		})
+	}
+}
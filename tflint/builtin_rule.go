@@ -0,0 +1,118 @@
+package tflint
+
+import sdk "github.com/nholuongut/tflint-plugin-sdk/tflint"
+
+// builtinRule is a rule implemented in TFLint itself rather than plugins.
+// These rules report problems with inputs to Terraform, such as values in tfvars files,
+// which can be detected without provider-specific knowledge.
+type builtinRule struct {
+	name     string
+	severity Severity
+	link     string
+}
+
+var _ Rule = (*builtinRule)(nil)
+
+func (r *builtinRule) Name() string       { return r.name }
+func (r *builtinRule) Severity() Severity { return r.severity }
+func (r *builtinRule) Link() string       { return r.link }
+
+// variableValidationRule reports input variable values that fail custom validation rules.
+var variableValidationRule = &builtinRule{
+	name:     "variable_validation",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/values/variables#custom-validation-rules",
+}
+
+// undeclaredVariableValueRule reports values in values files and run blocks of test files
+// that are assigned to undeclared variables.
+var undeclaredVariableValueRule = &builtinRule{
+	name:     "undeclared_variable_value",
+	severity: sdk.WARNING,
+	link:     "https://developer.hashicorp.com/terraform/language/values/variables#variable-definitions-tfvars-files",
+}
+
+// variableValueTypeRule reports input variable values that do not match the type constraints.
+var variableValueTypeRule = &builtinRule{
+	name:     "variable_value_type",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/values/variables#type-constraints",
+}
+
+// customConditionRule reports preconditions and postconditions that are known to fail.
+var customConditionRule = &builtinRule{
+	name:     "custom_condition",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/expressions/custom-conditions#preconditions-and-postconditions",
+}
+
+// checkAssertionRule reports assertions in check blocks that are known to fail.
+// Like Terraform, this is a warning since check blocks do not block operations.
+var checkAssertionRule = &builtinRule{
+	name:     "check_assertion",
+	severity: sdk.WARNING,
+	link:     "https://developer.hashicorp.com/terraform/language/checks",
+}
+
+// moduleInputRule reports module call arguments that do not match the variables
+// declared in the called module.
+var moduleInputRule = &builtinRule{
+	name:     "module_input",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/modules/syntax#module-blocks",
+}
+
+// fileAccessRule reports calls of filesystem functions that refer to missing files
+// or files outside the sandbox directory.
+var fileAccessRule = &builtinRule{
+	name:     "file_access",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/functions/file",
+}
+
+// unavailableFunctionRule reports calls to functions that are not available
+// in the Terraform version specified by terraform_version.
+var unavailableFunctionRule = &builtinRule{
+	name:     "unavailable_function",
+	severity: sdk.ERROR,
+	link:     "https://developer.hashicorp.com/terraform/language/functions",
+}
+
+// annotationReasonRule reports annotations without a reason when require_annotation_reason is enabled.
+var annotationReasonRule = &builtinRule{
+	name:     "annotation_reason",
+	severity: sdk.ERROR,
+	link:     "https://github.com/nholuongut/tflint/blob/master/docs/user-guide/annotations.md",
+}
+
+// expiredAnnotationRule reports annotations whose expiry date has passed.
+// Expired annotations no longer ignore issues.
+var expiredAnnotationRule = &builtinRule{
+	name:     "expired_annotation",
+	severity: sdk.WARNING,
+	link:     "https://github.com/nholuongut/tflint/blob/master/docs/user-guide/annotations.md",
+}
+
+// unusedAnnotationRule reports annotations that did not ignore any issues or
+// refer to unknown rules. This is reported only if report_unused_annotations is enabled.
+var unusedAnnotationRule = &builtinRule{
+	name:     "unused_annotation",
+	severity: sdk.WARNING,
+	link:     "https://github.com/nholuongut/tflint/blob/master/docs/user-guide/annotations.md",
+}
+
+// builtinRules is the list of all built-in rules.
+// Annotations can refer to these rules regardless of the loaded plugins.
+var builtinRules = []Rule{
+	variableValidationRule,
+	undeclaredVariableValueRule,
+	variableValueTypeRule,
+	customConditionRule,
+	checkAssertionRule,
+	moduleInputRule,
+	fileAccessRule,
+	unavailableFunctionRule,
+	annotationReasonRule,
+	expiredAnnotationRule,
+	unusedAnnotationRule,
+}
@@ -1,11 +1,17 @@
 package tflint
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/hashicorp/go-version"
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -16,6 +22,8 @@ import (
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
 	sdk "github.com/nholuongut/tflint-plugin-sdk/tflint"
 	"github.com/nholuongut/tflint/terraform"
+	"github.com/nholuongut/tflint/terraform/lang"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var defaultConfigFile = ".tflint.hcl"
@@ -37,6 +45,13 @@ var configSchema = &hcl.BodySchema{
 			Type:       "plugin",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       "scenario",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "ignore",
+		},
 	},
 }
 
@@ -50,6 +65,13 @@ var innerConfigSchema = &hcl.BodySchema{
 		{Name: "disabled_by_default"},
 		{Name: "plugin_dir"},
 		{Name: "format"},
+		{Name: "language"},
+		{Name: "workspace"},
+		{Name: "sandbox_dir"},
+		{Name: "deterministic"},
+		{Name: "terraform_version"},
+		{Name: "require_annotation_reason"},
+		{Name: "report_unused_annotations"},
 
 		// Removed attributes
 		{Name: "module"},
@@ -82,12 +104,31 @@ type Config struct {
 	Format    string
 	FormatSet bool
 
+	Language terraform.Language
+
+	TerraformVersion string
+
+	RequireAnnotationReason bool
+
+	ReportUnusedAnnotations    bool
+	ReportUnusedAnnotationsSet bool
+
+	Workspaces []string
+
+	SandboxDir string
+
+	Deterministic        bool
+	DeterministicSet     bool
+	DeterministicResults map[string]string
+
 	Varfiles      []string
 	Variables     []string
 	Only          []string
 	IgnoreModules map[string]bool
 	Rules         map[string]*RuleConfig
 	Plugins       map[string]*PluginConfig
+	Scenarios     []*ScenarioConfig
+	Ignores       []*IgnoreConfig
 
 	sources map[string][]byte
 }
@@ -115,6 +156,23 @@ type PluginConfig struct {
 	SourceRepo  string
 }
 
+// ScenarioConfig is a set of input variables to inspect the module with.
+// Values in a scenario take precedence over the global varfile and variables.
+type ScenarioConfig struct {
+	Name      string   `hcl:"name,label"`
+	Varfiles  []string `hcl:"varfile,optional"`
+	Variables []string `hcl:"variables,optional"`
+}
+
+// IgnoreConfig suppresses issues of the rule that match the paths and addresses.
+// Suppressed issues are not reported except in JSON output.
+type IgnoreConfig struct {
+	Rule      string   `hcl:"rule"`
+	Paths     []string `hcl:"paths,optional"`
+	Addresses []string `hcl:"addresses,optional"`
+	Reason    string   `hcl:"reason,optional"`
+}
+
 // EmptyConfig returns default config
 // It is mainly used for testing
 func EmptyConfig() *Config {
@@ -300,6 +358,79 @@ func loadConfig(file afero.File) (*Config, error) {
 						return config, fmt.Errorf("%s is invalid format. Allowed formats are: %s", config.Format, strings.Join(validFormats, ", "))
 					}
 
+				case "language":
+					var language string
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &language); err != nil {
+						return config, err
+					}
+					config.Language, err = terraform.AsLanguage(language)
+					if err != nil {
+						return config, err
+					}
+
+				case "terraform_version":
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.TerraformVersion); err != nil {
+						return config, err
+					}
+					if _, err := version.NewVersion(config.TerraformVersion); err != nil {
+						return config, fmt.Errorf(`"%s" is invalid terraform_version; %w`, config.TerraformVersion, err)
+					}
+
+				case "require_annotation_reason":
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.RequireAnnotationReason); err != nil {
+						return config, err
+					}
+
+				case "report_unused_annotations":
+					config.ReportUnusedAnnotationsSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.ReportUnusedAnnotations); err != nil {
+						return config, err
+					}
+
+				case "workspace":
+					// Both a single workspace and a list of workspaces are allowed
+					val, diags := attr.Expr.Value(nil)
+					if diags.HasErrors() {
+						return config, diags
+					}
+					if val.Type() == cty.String {
+						var workspace string
+						if err := gohcl.DecodeExpression(attr.Expr, nil, &workspace); err != nil {
+							return config, err
+						}
+						config.Workspaces = []string{workspace}
+					} else {
+						if err := gohcl.DecodeExpression(attr.Expr, nil, &config.Workspaces); err != nil {
+							return config, err
+						}
+					}
+
+				case "sandbox_dir":
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.SandboxDir); err != nil {
+						return config, err
+					}
+
+				case "deterministic":
+					// Both a bool and a map of function results are allowed
+					val, diags := attr.Expr.Value(nil)
+					if diags.HasErrors() {
+						return config, diags
+					}
+					config.DeterministicSet = true
+					if val.Type() == cty.Bool {
+						if err := gohcl.DecodeExpression(attr.Expr, nil, &config.Deterministic); err != nil {
+							return config, err
+						}
+					} else {
+						config.Deterministic = true
+						if err := gohcl.DecodeExpression(attr.Expr, nil, &config.DeterministicResults); err != nil {
+							return config, err
+						}
+						if err := validateDeterministicResults(config.DeterministicResults); err != nil {
+							return config, err
+						}
+					}
+
 				// Removed attributes
 				case "module":
 					return config, fmt.Errorf(`"module" attribute was removed in v0.54.0. Use "call_module_type" instead`)
@@ -326,6 +457,28 @@ func loadConfig(file afero.File) (*Config, error) {
 			}
 			config.Plugins[block.Labels[0]] = pluginConfig
 
+		case "scenario":
+			scenarioConfig := &ScenarioConfig{Name: block.Labels[0]}
+			if err := gohcl.DecodeBody(block.Body, nil, scenarioConfig); err != nil {
+				return config, err
+			}
+			for _, scenario := range config.Scenarios {
+				if scenario.Name == scenarioConfig.Name {
+					return config, fmt.Errorf(`scenario "%s" is declared multiple times`, scenarioConfig.Name)
+				}
+			}
+			config.Scenarios = append(config.Scenarios, scenarioConfig)
+
+		case "ignore":
+			ignoreConfig := &IgnoreConfig{}
+			if err := gohcl.DecodeBody(block.Body, nil, ignoreConfig); err != nil {
+				return config, err
+			}
+			if err := ignoreConfig.validate(); err != nil {
+				return config, err
+			}
+			config.Ignores = append(config.Ignores, ignoreConfig)
+
 		default:
 			panic("never happened")
 		}
@@ -342,6 +495,19 @@ func loadConfig(file afero.File) (*Config, error) {
 	log.Printf("[DEBUG]   PluginDirSet: %t", config.PluginDirSet)
 	log.Printf("[DEBUG]   Format: %s", config.Format)
 	log.Printf("[DEBUG]   FormatSet: %t", config.FormatSet)
+	log.Printf("[DEBUG]   Language: %s", config.Language)
+	log.Printf("[DEBUG]   TerraformVersion: %s", config.TerraformVersion)
+	log.Printf("[DEBUG]   RequireAnnotationReason: %t", config.RequireAnnotationReason)
+	log.Printf("[DEBUG]   ReportUnusedAnnotations: %t", config.ReportUnusedAnnotations)
+	log.Printf("[DEBUG]   ReportUnusedAnnotationsSet: %t", config.ReportUnusedAnnotationsSet)
+	log.Printf("[DEBUG]   Workspaces: %s", strings.Join(config.Workspaces, ", "))
+	log.Printf("[DEBUG]   SandboxDir: %s", config.SandboxDir)
+	log.Printf("[DEBUG]   Deterministic: %t", config.Deterministic)
+	log.Printf("[DEBUG]   DeterministicSet: %t", config.DeterministicSet)
+	log.Printf("[DEBUG]   DeterministicResults:")
+	for name, result := range config.DeterministicResults {
+		log.Printf("[DEBUG]     %s: %s", name, result)
+	}
 	log.Printf("[DEBUG]   Varfiles: %s", strings.Join(config.Varfiles, ", "))
 	log.Printf("[DEBUG]   Variables: %s", strings.Join(config.Variables, ", "))
 	log.Printf("[DEBUG]   Only: %s", strings.Join(config.Only, ", "))
@@ -357,6 +523,14 @@ func loadConfig(file afero.File) (*Config, error) {
 	for name, plugin := range config.Plugins {
 		log.Printf("[DEBUG]     %s: enabled=%t, version=%s, source=%s", name, plugin.Enabled, plugin.Version, plugin.Source)
 	}
+	log.Printf("[DEBUG]   Scenarios:")
+	for _, scenario := range config.Scenarios {
+		log.Printf("[DEBUG]     %s: varfile=%s, variables=%s", scenario.Name, strings.Join(scenario.Varfiles, ", "), strings.Join(scenario.Variables, ", "))
+	}
+	log.Printf("[DEBUG]   Ignores:")
+	for _, ignore := range config.Ignores {
+		log.Printf("[DEBUG]     %s: paths=%s, addresses=%s, reason=%s", ignore.Rule, strings.Join(ignore.Paths, ", "), strings.Join(ignore.Addresses, ", "), ignore.Reason)
+	}
 
 	return config, nil
 }
@@ -514,9 +688,39 @@ func (c *Config) Merge(other *Config) {
 		c.Format = other.Format
 	}
 
+	// Workspaces are not merged because it is a set of workspaces to be inspected
+	if len(other.Workspaces) > 0 {
+		c.Workspaces = other.Workspaces
+	}
+
+	if len(other.Scenarios) > 0 {
+		c.Scenarios = other.Scenarios
+	}
+
+	if other.SandboxDir != "" {
+		c.SandboxDir = other.SandboxDir
+	}
+
+	if other.DeterministicSet {
+		c.DeterministicSet = true
+		c.Deterministic = other.Deterministic
+	}
+	if len(other.DeterministicResults) > 0 && c.DeterministicResults == nil {
+		c.DeterministicResults = map[string]string{}
+	}
+	for name, result := range other.DeterministicResults {
+		c.DeterministicResults[name] = result
+	}
+
+	if other.ReportUnusedAnnotationsSet {
+		c.ReportUnusedAnnotationsSet = true
+		c.ReportUnusedAnnotations = other.ReportUnusedAnnotations
+	}
+
 	c.Varfiles = append(c.Varfiles, other.Varfiles...)
 	c.Variables = append(c.Variables, other.Variables...)
 	c.Only = append(c.Only, other.Only...)
+	c.Ignores = append(c.Ignores, other.Ignores...)
 
 	for name, ignore := range other.IgnoreModules {
 		c.IgnoreModules[name] = ignore
@@ -543,6 +747,33 @@ func (c *Config) Merge(other *Config) {
 	}
 }
 
+// Workspace returns the workspace used as `terraform.workspace`.
+// If workspaces are set, the first one is used. Otherwise, it is detected
+// from the environment in the same way as Terraform.
+func (c *Config) Workspace() string {
+	if len(c.Workspaces) > 0 {
+		return c.Workspaces[0]
+	}
+	return terraform.Workspace()
+}
+
+// ForWorkspace returns a shallow copy of the config pinned to the given workspace.
+// It is used to inspect the same module against multiple workspaces.
+func (c *Config) ForWorkspace(name string) *Config {
+	ret := *c
+	ret.Workspaces = []string{name}
+	return &ret
+}
+
+// ForScenario returns a shallow copy of the config with the input variables of the scenario.
+// The scenario's values are applied after the global varfile and variables.
+func (c *Config) ForScenario(scenario *ScenarioConfig) *Config {
+	ret := *c
+	ret.Varfiles = append(slices.Clone(c.Varfiles), scenario.Varfiles...)
+	ret.Variables = append(slices.Clone(c.Variables), scenario.Variables...)
+	return &ret
+}
+
 // ToPluginConfig converts self into the plugin configuration format
 func (c *Config) ToPluginConfig() *sdk.Config {
 	cfg := &sdk.Config{
@@ -599,15 +830,40 @@ func (c *Config) ValidateRules(rulesets ...RuleSet) error {
 		}
 	}
 
+	// Built-in rules can be configured like plugin rules
+	for _, rule := range builtinRules {
+		if _, exists := rulesMap[rule.Name()]; !exists {
+			rulesMap[rule.Name()] = "builtin"
+		}
+	}
+
 	for _, rule := range c.Rules {
 		if _, exists := rulesMap[rule.Name]; !exists {
 			return fmt.Errorf("Rule not found: %s", rule.Name)
 		}
 	}
+	for _, ignore := range c.Ignores {
+		if _, exists := rulesMap[ignore.Rule]; !exists && ignore.Rule != "all" {
+			return fmt.Errorf("Rule not found in ignore block: %s", ignore.Rule)
+		}
+	}
 
 	return nil
 }
 
+// builtinRuleEnabled returns whether the built-in rule is enabled.
+// Like plugin rules, --only takes precedence over rule blocks, and rules without
+// a rule block are enabled unless disabled_by_default is set.
+func (c *Config) builtinRuleEnabled(name string) bool {
+	if len(c.Only) > 0 {
+		return slices.Contains(c.Only, name)
+	}
+	if rule, exists := c.Rules[name]; exists {
+		return rule.Enabled
+	}
+	return !c.DisabledByDefault
+}
+
 func (c *PluginConfig) validate() error {
 	if c.Version != "" && c.Source == "" {
 		return fmt.Errorf(`plugin "%s": "source" attribute cannot be omitted when specifying "version"`, c.Name)
@@ -631,3 +887,67 @@ func (c *PluginConfig) validate() error {
 
 	return nil
 }
+
+func (c *IgnoreConfig) validate() error {
+	if c.Rule == "" {
+		return errors.New(`ignore: "rule" attribute must not be empty`)
+	}
+	// doublestar reports syntax errors lazily, so match the pattern against itself
+	// to walk the whole pattern.
+	for _, pattern := range c.Paths {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return fmt.Errorf(`ignore "%s": "%s" is an invalid path pattern; %w`, c.Rule, pattern, err)
+		}
+	}
+	for _, pattern := range c.Addresses {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return fmt.Errorf(`ignore "%s": "%s" is an invalid address pattern; %w`, c.Rule, pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsAffected checks if the passed issue is suppressed by the ignore block.
+// The path is the path of the file where the issue was found, relative to the module directory.
+// The address is the address of the block where the issue was found,
+// and is only evaluated if address patterns are given.
+func (c *IgnoreConfig) IsAffected(issue *Issue, path string, address func() string) bool {
+	if c.Rule != "all" && c.Rule != issue.Rule.Name() {
+		return false
+	}
+	if len(c.Paths) > 0 && !matchPatterns(c.Paths, filepath.ToSlash(path)) {
+		return false
+	}
+	if len(c.Addresses) > 0 && !matchPatterns(c.Addresses, address()) {
+		return false
+	}
+	return true
+}
+
+func matchPatterns(patterns []string, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, _ := doublestar.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDeterministicResults checks that the results are given for impure functions
+// and the timestamps are valid, so that formatdate and timeadd can handle them.
+func validateDeterministicResults(results map[string]string) error {
+	for name, result := range results {
+		if _, exists := lang.DefaultDeterministicResults[name]; !exists {
+			return fmt.Errorf(`"%s" is not an impure function. Allowed functions in deterministic are: %s`, name, strings.Join(slices.Sorted(maps.Keys(lang.DefaultDeterministicResults)), ", "))
+		}
+		if name == "timestamp" || name == "plantimestamp" {
+			if _, err := time.Parse(time.RFC3339, result); err != nil {
+				return fmt.Errorf(`"%s" in deterministic must be a RFC 3339 timestamp; %w`, name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -43,6 +44,15 @@ config {
 
 	call_module_type = "all"
 	force = true
+	language = "opentofu"
+	terraform_version = "1.5.0"
+	require_annotation_reason = true
+	report_unused_annotations = true
+	workspace = ["dev", "prod"]
+	sandbox_dir = "."
+	deterministic = {
+		timestamp = "2024-01-01T00:00:00Z"
+	}
 
 	ignore_module = {
 		"github.com/nholuongut/example-module" = true
@@ -76,13 +86,46 @@ plugin "bar" {
 plugin "baz" {
 	enabled = true
 	foo = "baz"
+}
+
+scenario "dev" {
+	varfile = ["dev.tfvars"]
+}
+
+scenario "prod" {
+	varfile = ["prod.tfvars"]
+	variables = ["instance_type=m5.large"]
+}
+
+ignore {
+	rule = "aws_instance_invalid_type"
+	paths = ["gen/**"]
+	addresses = ["aws_instance.legacy_*"]
+	reason = "Legacy instances will be removed"
+}
+
+ignore {
+	rule = "all"
+	paths = ["vendor/**/*.tf"]
 }`,
 			},
 			want: &Config{
-				CallModuleType:    terraform.CallAllModule,
-				CallModuleTypeSet: true,
-				Force:             true,
-				ForceSet:          true,
+				CallModuleType:             terraform.CallAllModule,
+				CallModuleTypeSet:          true,
+				Force:                      true,
+				ForceSet:                   true,
+				Language:                   terraform.LanguageOpenTofu,
+				TerraformVersion:           "1.5.0",
+				RequireAnnotationReason:    true,
+				ReportUnusedAnnotations:    true,
+				ReportUnusedAnnotationsSet: true,
+				Workspaces:                 []string{"dev", "prod"},
+				SandboxDir:                 ".",
+				Deterministic:              true,
+				DeterministicSet:           true,
+				DeterministicResults: map[string]string{
+					"timestamp": "2024-01-01T00:00:00Z",
+				},
 				IgnoreModules: map[string]bool{
 					"github.com/nholuongut/example-module": true,
 				},
@@ -127,6 +170,29 @@ plugin "baz" {
 						Enabled: true,
 					},
 				},
+				Scenarios: []*ScenarioConfig{
+					{
+						Name:     "dev",
+						Varfiles: []string{"dev.tfvars"},
+					},
+					{
+						Name:      "prod",
+						Varfiles:  []string{"prod.tfvars"},
+						Variables: []string{"instance_type=m5.large"},
+					},
+				},
+				Ignores: []*IgnoreConfig{
+					{
+						Rule:      "aws_instance_invalid_type",
+						Paths:     []string{"gen/**"},
+						Addresses: []string{"aws_instance.legacy_*"},
+						Reason:    "Legacy instances will be removed",
+					},
+					{
+						Rule:  "all",
+						Paths: []string{"vendor/**/*.tf"},
+					},
+				},
 			},
 			errCheck: neverHappend,
 		},
@@ -137,6 +203,57 @@ plugin "baz" {
 			want:     EmptyConfig().enableBundledPlugin(),
 			errCheck: neverHappend,
 		},
+		{
+			name: "single workspace",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+	workspace = "prod"
+}`,
+			},
+			want: &Config{
+				CallModuleType: terraform.CallLocalModule,
+				Workspaces:     []string{"prod"},
+				IgnoreModules:  map[string]bool{},
+				Varfiles:       []string{},
+				Variables:      []string{},
+				Rules:          map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "deterministic",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+	deterministic = true
+}`,
+			},
+			want: &Config{
+				CallModuleType:   terraform.CallLocalModule,
+				Deterministic:    true,
+				DeterministicSet: true,
+				IgnoreModules:    map[string]bool{},
+				Varfiles:         []string{},
+				Variables:        []string{},
+				Rules:            map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
 		{
 			name: "TFLINT_CONFIG_FILE",
 			file: "",
@@ -270,6 +387,50 @@ rule "aws_instance_invalid_type" "myrule" {
 				return err == nil || err.Error() != "invalid.hcl:2,34-42: Extraneous label for rule; Only 1 labels (name) are expected for rule blocks."
 			},
 		},
+		{
+			name: "duplicate scenarios",
+			file: "duplicate_scenarios.hcl",
+			files: map[string]string{
+				"duplicate_scenarios.hcl": `
+scenario "dev" {
+	varfile = ["dev.tfvars"]
+}
+
+scenario "dev" {
+	varfile = ["dev2.tfvars"]
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `scenario "dev" is declared multiple times`
+			},
+		},
+		{
+			name: "invalid ignore path pattern",
+			file: "invalid_ignore.hcl",
+			files: map[string]string{
+				"invalid_ignore.hcl": `
+ignore {
+	rule = "aws_instance_invalid_type"
+	paths = ["gen/[a-"]
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `ignore "aws_instance_invalid_type": "gen/[a-" is an invalid path pattern; syntax error in pattern`
+			},
+		},
+		{
+			name: "ignore without rule",
+			file: "ignore_without_rule.hcl",
+			files: map[string]string{
+				"ignore_without_rule.hcl": `
+ignore {
+	rule = ""
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `ignore: "rule" attribute must not be empty`
+			},
+		},
 		{
 			name: "invalid format",
 			file: "invalid_format.hcl",
@@ -296,6 +457,62 @@ config {
 				return err == nil || err.Error() != "invalid is invalid call module type. Allowed values are: all, local, none"
 			},
 		},
+		{
+			name: "invalid language",
+			file: "invalid_language.hcl",
+			files: map[string]string{
+				"invalid_language.hcl": `
+config {
+	language = "invalid"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != "invalid is invalid language. Allowed values are: auto, terraform, opentofu"
+			},
+		},
+		{
+			name: "invalid terraform_version",
+			file: "invalid_terraform_version.hcl",
+			files: map[string]string{
+				"invalid_terraform_version.hcl": `
+config {
+	terraform_version = "latest"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `"latest" is invalid terraform_version; Malformed version: latest`
+			},
+		},
+		{
+			name: "deterministic with pure function",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+	deterministic = {
+		upper = "FOO"
+	}
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `"upper" is not an impure function. Allowed functions in deterministic are: bcrypt, plantimestamp, timestamp, uuid`
+			},
+		},
+		{
+			name: "deterministic with invalid timestamp",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+	deterministic = {
+		timestamp = "2024-01-01"
+	}
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || !strings.HasPrefix(err.Error(), `"timestamp" in deterministic must be a RFC 3339 timestamp`)
+			},
+		},
 		{
 			name: "plugin without source",
 			file: "plugin_without_source.hcl",
@@ -582,6 +799,9 @@ func TestMerge(t *testing.T) {
 				},
 				Varfiles:             []string{"example1.tfvars", "example2.tfvars"},
 				Variables:            []string{"foo=bar"},
+				Workspaces:           []string{"default"},
+				SandboxDir:           ".",
+				DeterministicResults: map[string]string{"timestamp": "2024-01-01T00:00:00Z"},
 				DisabledByDefault:    true,
 				DisabledByDefaultSet: true,
 				PluginDir:            "./.tflint.d/plugins",
@@ -620,14 +840,21 @@ func TestMerge(t *testing.T) {
 					"github.com/nholuongut/example-2": true,
 					"github.com/nholuongut/example-3": false,
 				},
-				Varfiles:             []string{"example3.tfvars"},
-				Variables:            []string{"bar=baz"},
-				DisabledByDefault:    false,
-				DisabledByDefaultSet: true,
-				PluginDir:            "~/.tflint.d/plugins",
-				PluginDirSet:         true,
-				Format:               "json",
-				FormatSet:            true,
+				Varfiles:                   []string{"example3.tfvars"},
+				Variables:                  []string{"bar=baz"},
+				Workspaces:                 []string{"dev", "prod"},
+				SandboxDir:                 "/sandbox",
+				Deterministic:              true,
+				DeterministicSet:           true,
+				DeterministicResults:       map[string]string{"uuid": "00000000-0000-0000-0000-000000000001"},
+				ReportUnusedAnnotations:    true,
+				ReportUnusedAnnotationsSet: true,
+				DisabledByDefault:          false,
+				DisabledByDefaultSet:       true,
+				PluginDir:                  "~/.tflint.d/plugins",
+				PluginDirSet:               true,
+				Format:                     "json",
+				FormatSet:                  true,
 				Rules: map[string]*RuleConfig{
 					"aws_instance_invalid_ami": {
 						Name:    "aws_instance_invalid_ami",
@@ -661,14 +888,24 @@ func TestMerge(t *testing.T) {
 					"github.com/nholuongut/example-2": true,
 					"github.com/nholuongut/example-3": false,
 				},
-				Varfiles:             []string{"example1.tfvars", "example2.tfvars", "example3.tfvars"},
-				Variables:            []string{"foo=bar", "bar=baz"},
-				DisabledByDefault:    false,
-				DisabledByDefaultSet: true,
-				PluginDir:            "~/.tflint.d/plugins",
-				PluginDirSet:         true,
-				Format:               "json",
-				FormatSet:            true,
+				Varfiles:         []string{"example1.tfvars", "example2.tfvars", "example3.tfvars"},
+				Variables:        []string{"foo=bar", "bar=baz"},
+				Workspaces:       []string{"dev", "prod"},
+				SandboxDir:       "/sandbox",
+				Deterministic:    true,
+				DeterministicSet: true,
+				DeterministicResults: map[string]string{
+					"timestamp": "2024-01-01T00:00:00Z",
+					"uuid":      "00000000-0000-0000-0000-000000000001",
+				},
+				ReportUnusedAnnotations:    true,
+				ReportUnusedAnnotationsSet: true,
+				DisabledByDefault:          false,
+				DisabledByDefaultSet:       true,
+				PluginDir:                  "~/.tflint.d/plugins",
+				PluginDirSet:               true,
+				Format:                     "json",
+				FormatSet:                  true,
 				Rules: map[string]*RuleConfig{
 					"aws_instance_invalid_type": {
 						Name:    "aws_instance_invalid_type",
@@ -951,6 +1188,29 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestForScenario(t *testing.T) {
+	config := EmptyConfig()
+	config.Varfiles = []string{"common.tfvars"}
+	config.Variables = []string{"foo=bar"}
+
+	got := config.ForScenario(&ScenarioConfig{
+		Name:      "prod",
+		Varfiles:  []string{"prod.tfvars"},
+		Variables: []string{"foo=baz"},
+	})
+
+	if diff := cmp.Diff([]string{"common.tfvars", "prod.tfvars"}, got.Varfiles); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"foo=bar", "foo=baz"}, got.Variables); diff != "" {
+		t.Error(diff)
+	}
+	// The original config must not be changed
+	if diff := cmp.Diff([]string{"common.tfvars"}, config.Varfiles); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func Test_ToPluginConfig(t *testing.T) {
 	src := `
 config {
@@ -1211,6 +1471,41 @@ func Test_ValidateRules(t *testing.T) {
 			RuleSets: []RuleSet{&ruleSetB{}},
 			Err:      errors.New("Rule not found: aws_instance_invalid_type"),
 		},
+		{
+			Name: "built-in rules",
+			Config: &Config{
+				Rules: map[string]*RuleConfig{
+					"custom_condition": {
+						Name:    "custom_condition",
+						Enabled: false,
+					},
+				},
+			},
+			RuleSets: []RuleSet{&ruleSetA{}},
+			Err:      nil,
+		},
+		{
+			Name: "ignore blocks",
+			Config: &Config{
+				Ignores: []*IgnoreConfig{
+					{Rule: "aws_instance_invalid_type"},
+					{Rule: "module_input"},
+					{Rule: "all"},
+				},
+			},
+			RuleSets: []RuleSet{&ruleSetA{}},
+			Err:      nil,
+		},
+		{
+			Name: "rule in ignore block not found",
+			Config: &Config{
+				Ignores: []*IgnoreConfig{
+					{Rule: "aws_instance_invalid_ami"},
+				},
+			},
+			RuleSets: []RuleSet{&ruleSetA{}},
+			Err:      errors.New("Rule not found in ignore block: aws_instance_invalid_ami"),
+		},
 	}
 
 	for _, tc := range cases {
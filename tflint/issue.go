@@ -17,15 +17,41 @@ type Issue struct {
 	Fixable bool
 	Callers []hcl.Range
 
+	// Workspaces and Scenarios are the lists of workspaces and scenarios
+	// where the issue was found. These are set only when multiple workspaces
+	// or scenarios are inspected and the issue is not found in all of them.
+	Workspaces []string
+	Scenarios  []string
+
+	// Suppression is set when the issue is ignored by an ignore block
+	// in the config file. Suppressed issues are reported only in JSON output.
+	Suppression *Suppression
+
 	// Source is the source code of the file where the issue was found.
 	// Usually this is the same as the originally loaded source,
 	// but it may be a different if rewritten by autofixes.
 	Source []byte
 }
 
+// Suppression represents why an issue was suppressed
+type Suppression struct {
+	Reason string `json:"reason"`
+}
+
 // Issues is an alias for the map of Issue
 type Issues []*Issue
 
+// Unsuppressed returns the issues that are not suppressed by ignore blocks
+func (issues Issues) Unsuppressed() Issues {
+	ret := Issues{}
+	for _, issue := range issues {
+		if issue.Suppression == nil {
+			ret = append(ret, issue)
+		}
+	}
+	return ret
+}
+
 // Severity indicates the severity of the issue
 type Severity = sdk.Severity
 
@@ -89,6 +115,11 @@ type issue struct {
 	Fixable bool        `json:"fixable"`
 	Callers []hcl.Range `json:"callers"`
 	Source  []byte      `json:"source"`
+
+	Workspaces []string `json:"workspaces,omitempty"`
+	Scenarios  []string `json:"scenarios,omitempty"`
+
+	Suppression *Suppression `json:"suppression,omitempty"`
 }
 
 type rule struct {
@@ -115,6 +146,11 @@ func (i *Issue) MarshalJSON() ([]byte, error) {
 		Fixable: i.Fixable,
 		Callers: i.Callers,
 		Source:  i.Source,
+
+		Workspaces: i.Workspaces,
+		Scenarios:  i.Scenarios,
+
+		Suppression: i.Suppression,
 	})
 }
 
@@ -131,6 +167,9 @@ func (i *Issue) UnmarshalJSON(data []byte) error {
 	i.Fixable = out.Fixable
 	i.Callers = out.Callers
 	i.Source = out.Source
+	i.Workspaces = out.Workspaces
+	i.Scenarios = out.Scenarios
+	i.Suppression = out.Suppression
 
 	return nil
 }
@@ -260,6 +260,22 @@ func TestMarshalJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "suppressed issues",
+			issues: Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 1},
+						End:      hcl.Pos{Line: 1, Column: 2, Byte: 2},
+					},
+					Callers:     []hcl.Range{},
+					Suppression: &Suppression{Reason: "generated code"},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
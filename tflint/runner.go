@@ -1,15 +1,26 @@
 package tflint
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/go-version"
 	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/nholuongut/tflint-plugin-sdk/hclext"
 	"github.com/nholuongut/tflint/terraform"
 	"github.com/nholuongut/tflint/terraform/addrs"
 	"github.com/nholuongut/tflint/terraform/lang"
+	"github.com/nholuongut/tflint/terraform/lang/funcs"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -21,11 +32,17 @@ type Runner struct {
 	Issues   Issues
 	Ctx      *terraform.Evaluator
 
-	annotations map[string]Annotations
-	config      *Config
-	currentExpr hcl.Expression
-	modVars     map[string]*moduleVariable
-	changes     map[string][]byte
+	annotations     map[string]Annotations
+	annotationUsage *annotationUsage
+	config          *Config
+	baseDir         string
+	currentExpr     hcl.Expression
+	modVars         map[string]*moduleVariable
+	changes         map[string][]byte
+
+	// issuesMu guards issues emitted by EvaluateExpr. The root runner is shared
+	// by the plugin servers of module runners, which are run concurrently.
+	issuesMu sync.Mutex
 }
 
 // Rule is interface for building the issue
@@ -49,29 +66,359 @@ func NewRunner(originalWorkingDir string, c *Config, ants map[string]Annotations
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	sandboxDir := ""
+	if c.SandboxDir != "" {
+		var err error
+		sandboxDir, err = filepath.Abs(c.SandboxDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var terraformVersion *version.Version
+	if c.TerraformVersion != "" {
+		var err error
+		terraformVersion, err = version.NewVersion(c.TerraformVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Files are loaded with paths relative to the original working directory (e.g. with --chdir),
+	// so keep the path of the working directory to resolve paths relative to the module.
+	baseDir := "."
+	if originalWorkingDir != "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		baseDir, err = filepath.Rel(originalWorkingDir, wd)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var deterministicResults map[string]string
+	if c.Deterministic {
+		deterministicResults = maps.Clone(lang.DefaultDeterministicResults)
+		maps.Copy(deterministicResults, c.DeterministicResults)
+	}
 	ctx := &terraform.Evaluator{
 		Meta: &terraform.ContextMeta{
-			Env:                terraform.Workspace(),
+			Env:                c.Workspace(),
 			OriginalWorkingDir: originalWorkingDir,
 		},
 		ModulePath:     cfg.Path.UnkeyedInstanceShim(),
 		Config:         cfg.Root,
 		VariableValues: variableValues,
+		SandboxDir:     sandboxDir,
+
+		DeterministicResults: deterministicResults,
+		TerraformVersion:     terraformVersion,
 	}
 
 	runner := &Runner{
 		TFConfig: cfg,
 		Issues:   Issues{},
 
-		Ctx:         ctx,
-		annotations: ants,
-		config:      c,
-		changes:     map[string][]byte{},
+		Ctx:             ctx,
+		annotations:     ants,
+		annotationUsage: newAnnotationUsage(),
+		config:          c,
+		baseDir:         baseDir,
+		changes:         map[string][]byte{},
+	}
+
+	// Values supplied externally (e.g. tfvars files) are only for the root module.
+	if cfg.Path.IsRoot() {
+		runner.checkInputValues(variables...)
+		runner.validateVariables(variables...)
+		runner.checkTestRuns()
+		runner.checkFunctionAvailability()
+		runner.checkAnnotations()
 	}
 
 	return runner, nil
 }
 
+// checkInputValues reports values for undeclared variables and values that do not match
+// the type constraints of the root module variables. Unlike validateVariables, all values
+// are checked even if overridden by others, so that problems in each values file are reported.
+//
+// Values for undeclared variables are only reported for values files. Undeclared
+// environment variables are allowed, and undeclared CLI flags are already rejected.
+func (r *Runner) checkInputValues(variables ...terraform.InputValues) {
+	for _, values := range variables {
+		supplied := terraform.InputValues{}
+		files := terraform.InputValues{}
+		for name, input := range values {
+			if input.SourceType == terraform.ValueFromUnknown {
+				continue
+			}
+			supplied[name] = input
+			if input.SourceType.IsFile() {
+				files[name] = input
+			}
+		}
+
+		for _, diag := range terraform.CheckUndeclaredValues(files, r.TFConfig.Module.Variables) {
+			r.EmitIssue(undeclaredVariableValueRule, diag.Detail, *diag.Subject, false)
+		}
+		for _, diag := range terraform.CheckValueTypes(supplied, r.TFConfig.Module.Variables) {
+			r.EmitIssue(variableValueTypeRule, diag.Detail, *diag.Subject, false)
+		}
+	}
+}
+
+// checkTestRuns reports values set in run blocks of test files that are assigned to
+// undeclared variables or do not match the type constraints of the module variables.
+//
+// Global variables in test files can be shared with alternate modules under test,
+// so only variables set in run blocks are checked for declarations. Run blocks that
+// test an alternate module are skipped.
+func (r *Runner) checkTestRuns() {
+	for _, path := range slices.Sorted(maps.Keys(r.TFConfig.Module.Tests)) {
+		file := r.TFConfig.Module.Tests[path]
+		for _, run := range file.Runs {
+			if run.Module != nil {
+				continue
+			}
+			values, diags := terraform.TestRunValues(file, run)
+			if diags.HasErrors() {
+				// Invalid expressions are not values to check, so ignore them here.
+				log.Printf("[DEBUG] Failed to evaluate variables of run.%s in %s: %s", run.Name, path, diags)
+			}
+
+			runValues := terraform.InputValues{}
+			for name := range run.Variables {
+				if value, exists := values[name]; exists {
+					runValues[name] = value
+				}
+			}
+
+			for _, diag := range terraform.CheckUndeclaredValues(runValues, r.TFConfig.Module.Variables) {
+				r.emitTestRunIssue(undeclaredVariableValueRule, diag)
+			}
+			for _, diag := range terraform.CheckValueTypes(values, r.TFConfig.Module.Variables) {
+				r.emitTestRunIssue(variableValueTypeRule, diag)
+			}
+		}
+	}
+}
+
+// emitTestRunIssue emits an issue for the diagnostic in a test file. Global variables
+// are shared by all run blocks, so the same issue is reported only once.
+func (r *Runner) emitTestRunIssue(rule Rule, diag *hcl.Diagnostic) {
+	if r.hasIssue(rule, diag.Detail, *diag.Subject) {
+		return
+	}
+	r.EmitIssue(rule, diag.Detail, *diag.Subject, false)
+}
+
+// validateVariables evaluates the custom validation rules of the root module variables
+// against the values supplied externally, such as values in tfvars files and CLI flags.
+// Failed validations are reported as issues at the location where the value is supplied.
+//
+// Default values are not validated here. Variables without supplied values are skipped.
+func (r *Runner) validateVariables(variables ...terraform.InputValues) {
+	supplied := terraform.InputValues{}.Override(variables...)
+
+	names := make([]string, 0, len(supplied))
+	for name := range supplied {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		input := supplied[name]
+		variable, declared := r.TFConfig.Module.Variables[name]
+		if !declared || input.SourceType == terraform.ValueFromUnknown {
+			continue
+		}
+
+		for _, validation := range variable.Validations {
+			ok, msg, diags := r.Ctx.EvaluateCheckRule(validation)
+			if diags.HasErrors() {
+				log.Printf("[DEBUG] Failed to evaluate the validation rule of var.%s: %s", name, diags)
+				continue
+			}
+			if ok {
+				continue
+			}
+
+			if msg == "" {
+				msg = fmt.Sprintf("The value does not satisfy the validation rule declared at %s.", validation.DeclRange)
+			}
+			r.EmitIssue(variableValidationRule, fmt.Sprintf("Invalid value for var.%s: %s", name, msg), input.SourceRange, false)
+		}
+	}
+}
+
+// checkFunctionAvailability reports calls to functions that are not available in
+// the target Terraform version. These calls are evaluated as unknown values.
+//
+// Unlike other issues in module runners, function calls in local modules are reported
+// where they are written, as they cannot be attributed to module call arguments.
+func (r *Runner) checkFunctionAvailability() {
+	if r.Ctx.TerraformVersion == nil {
+		return
+	}
+
+	files := r.TFConfig.Module.Files
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		calls, diags := lang.FunctionCallsInBody(files[name].Body)
+		if diags.HasErrors() {
+			// Invalid expressions are reported when evaluated by rules, so ignore them here.
+			log.Printf("[DEBUG] Failed to find function calls in %s: %s", name, diags)
+		}
+		for _, call := range calls {
+			if lang.FunctionAvailable(call.Name, r.Ctx.TerraformVersion) {
+				continue
+			}
+			r.emitIssue(&Issue{
+				Rule:    unavailableFunctionRule,
+				Message: fmt.Sprintf(`Function "%s" is not available in Terraform v%s. It was introduced in v%s.`, call.Name, r.Ctx.TerraformVersion, lang.FunctionIntroducedIn(call.Name)),
+				Range:   call.NameRange,
+				Source:  r.Sources()[call.NameRange.Filename],
+			})
+		}
+	}
+}
+
+// checkAnnotations reports annotations without a reason if required, and expired annotations.
+// These issues are not ignored by annotations, since an annotation could otherwise ignore itself.
+func (r *Runner) checkAnnotations() {
+	for _, filename := range slices.Sorted(maps.Keys(r.annotations)) {
+		for _, annotation := range r.annotations[filename] {
+			reason, expiry := annotation.Justification()
+
+			if r.config.RequireAnnotationReason && reason == "" && r.ruleEnabled(annotationReasonRule) {
+				r.Issues = append(r.Issues, &Issue{
+					Rule:    annotationReasonRule,
+					Message: `Annotation must have a reason. Write it after "--", e.g. "tflint-ignore: rule_name -- reason"`,
+					Range:   annotation.Range(),
+					Source:  r.Sources()[filename],
+				})
+			}
+			if annotationExpired(expiry) && r.ruleEnabled(expiredAnnotationRule) {
+				r.Issues = append(r.Issues, &Issue{
+					Rule:    expiredAnnotationRule,
+					Message: fmt.Sprintf("Annotation expired after %s and no longer ignores issues", expiry.Format(time.DateOnly)),
+					Range:   annotation.Range(),
+					Source:  r.Sources()[filename],
+				})
+			}
+		}
+	}
+}
+
+// UnusedAnnotationIssues returns issues for annotations that did not ignore any issues
+// in this runner and its module runners, and for annotations that refer to rules not
+// in the passed rule names. Built-in rules are always known.
+//
+// This should be called on the root module runner after all checks are performed.
+func (r *Runner) UnusedAnnotationIssues(ruleNames []string) Issues {
+	issues := Issues{}
+	if !r.ruleEnabled(unusedAnnotationRule) {
+		return issues
+	}
+
+	known := map[string]bool{"all": true}
+	for _, name := range ruleNames {
+		known[name] = true
+	}
+	for _, rule := range builtinRules {
+		known[rule.Name()] = true
+	}
+
+	for _, filename := range slices.Sorted(maps.Keys(r.annotations)) {
+		for _, annotation := range r.annotations[filename] {
+			// Expired annotations are reported by expired_annotation
+			if _, expiry := annotation.Justification(); annotationExpired(expiry) {
+				continue
+			}
+
+			unknown := []string{}
+			for _, rule := range annotation.Rules() {
+				if !known[rule] {
+					unknown = append(unknown, fmt.Sprintf(`"%s"`, rule))
+				}
+			}
+
+			var message string
+			switch {
+			case len(unknown) > 0:
+				message = fmt.Sprintf("Annotation refers to unknown rules: %s", strings.Join(unknown, ", "))
+			case !r.annotationUsage.isUsed(annotation):
+				message = "Annotation did not ignore any issues"
+			default:
+				continue
+			}
+			issues = append(issues, &Issue{
+				Rule:    unusedAnnotationRule,
+				Message: message,
+				Range:   annotation.Range(),
+				Source:  r.Sources()[filename],
+			})
+		}
+	}
+	return issues
+}
+
+// CheckConditions evaluates custom conditions in the module, such as preconditions and
+// postconditions of resources, preconditions of outputs, and assertions of check blocks.
+// Failed conditions are reported as issues at the condition expression. Conditions that
+// cannot be determined statically (e.g. references to unknown values) are ignored.
+//
+// For child modules, issues are reported to the module call arguments referenced by
+// the conditions, like issues emitted by plugins. Call this after NewModuleRunners.
+func (r *Runner) CheckConditions() {
+	module := r.TFConfig.Module
+
+	for _, resources := range []map[string]map[string]*terraform.Resource{module.Resources, module.DataResources, module.EphemeralResources} {
+		for _, ty := range slices.Sorted(maps.Keys(resources)) {
+			for _, name := range slices.Sorted(maps.Keys(resources[ty])) {
+				resource := resources[ty][name]
+				for _, rule := range resource.Preconditions {
+					r.checkCondition(customConditionRule, "Resource precondition failed", rule)
+				}
+				for _, rule := range resource.Postconditions {
+					r.checkCondition(customConditionRule, "Resource postcondition failed", rule)
+				}
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(module.Outputs)) {
+		for _, rule := range module.Outputs[name].Preconditions {
+			r.checkCondition(customConditionRule, "Module output value precondition failed", rule)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(module.Checks)) {
+		for _, rule := range module.Checks[name].Asserts {
+			r.checkCondition(checkAssertionRule, "Check block assertion failed", rule)
+		}
+	}
+}
+
+func (r *Runner) checkCondition(rule Rule, summary string, condition *terraform.CheckRule) {
+	ok, msg, diags := r.Ctx.EvaluateCheckRule(condition)
+	if diags.HasErrors() {
+		log.Printf("[DEBUG] Failed to evaluate the condition at %s: %s", condition.DeclRange, diags)
+		return
+	}
+	if ok {
+		return
+	}
+
+	if msg == "" {
+		msg = "The condition evaluated to false."
+	}
+	// HINT: WithExpressionContext never returns errors since the passed function doesn't return errors.
+	_ = r.WithExpressionContext(condition.Condition, func() error {
+		r.EmitIssue(rule, fmt.Sprintf("%s: %s", summary, msg), condition.Condition.Range(), false)
+		return nil
+	})
+}
+
 // NewModuleRunners returns new TFLint runners for child modules
 // Recursively search modules and generate Runners
 // In order to propagate attributes of moduleCall as variables to the module,
@@ -117,6 +464,19 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 			}
 		}
 
+		// Query all arguments, including undeclared ones, to check them against the variables.
+		moduleCallArgs, diags := parent.TFConfig.Module.PartialContent(moduleCallArgsSchema, parent.Ctx)
+		if diags.HasErrors() {
+			log.Printf("[DEBUG] Failed to get the arguments of %s module call: %s", moduleCall.Name, diags)
+		}
+		var moduleCallArgBodies []*hclext.BodyContent
+		for _, block := range moduleCallArgs.Blocks {
+			if moduleCall.Name == block.Labels[0] {
+				moduleCallArgBodies = append(moduleCallArgBodies, block.Body)
+			}
+		}
+
+		instanceInputs := []terraform.InputValues{}
 		for _, body := range moduleCallBodies {
 			modVars := map[string]*moduleVariable{}
 			inputs := terraform.InputValues{}
@@ -153,11 +513,15 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 				}
 			}
 
+			instanceInputs = append(instanceInputs, inputs)
+
 			runner, err := NewRunner(parent.Ctx.Meta.OriginalWorkingDir, parent.config, parent.annotations, cfg, inputs)
 			if err != nil {
 				return runners, err
 			}
 			runner.modVars = modVars
+			// Annotations ignoring issues in module calls are recorded in the root module runner
+			runner.annotationUsage = parent.annotationUsage
 			runners = append(runners, runner)
 			moduleRunners, err := NewModuleRunners(runner)
 			if err != nil {
@@ -165,11 +529,102 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 			}
 			runners = append(runners, moduleRunners...)
 		}
+
+		// All instances share the arguments, so they are checked once per module call.
+		if len(moduleCallArgBodies) > 0 {
+			parent.checkModuleCallArguments(moduleCall, moduleCallArgBodies[0].Attributes, instanceInputs, cfg.Module.Variables)
+		}
+	}
+
+	// Function calls in local modules are checked once per module directory,
+	// since instances and module calls sharing the directory have the same calls.
+	if parent.TFConfig.Path.IsRoot() {
+		checked := map[string]bool{}
+		for _, runner := range runners {
+			dir := runner.TFConfig.Module.SourceDir
+			if checked[dir] || !localModule(runner.TFConfig) {
+				continue
+			}
+			checked[dir] = true
+			runner.checkFunctionAvailability()
+		}
 	}
 
 	return runners, nil
 }
 
+// localModule returns true if the module and all its callers are called with local paths.
+// Such modules are a part of the inspected configuration, unlike installed modules.
+func localModule(cfg *terraform.Config) bool {
+	current := cfg.Root
+	for _, name := range cfg.Path {
+		call, exists := current.Module.ModuleCalls[name]
+		if !exists {
+			return false
+		}
+		if _, ok := call.SourceAddr.(addrs.ModuleSourceLocal); !ok {
+			return false
+		}
+		current = current.Children[name]
+	}
+	return true
+}
+
+var moduleCallArgsSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+			Body:       &hclext.BodySchema{Mode: hclext.SchemaJustAttributesMode},
+		},
+	},
+}
+
+// checkModuleCallArguments reports arguments of the module call that do not match the
+// variables declared in the called module, such as missing required arguments, undeclared
+// arguments, and values that cannot be converted to the type constraints.
+//
+// Issues are reported at the module call in the parent module. If the parent is also
+// a child module, they are reported to the module call arguments referenced by the expression.
+//
+// The values are checked for each instance expanded by count/for_each, but the same
+// problem found in multiple instances is reported only once.
+func (r *Runner) checkModuleCallArguments(call *terraform.ModuleCall, args hclext.Attributes, instanceInputs []terraform.InputValues, declVars map[string]*terraform.Variable) {
+	diags := terraform.CheckModuleCallArguments(call, args, declVars)
+
+	for _, inputs := range instanceInputs {
+		values := terraform.InputValues{}
+		for name, input := range inputs {
+			if arg, exists := args[name]; exists {
+				val, _ := input.Value.UnmarkDeep()
+				values[name] = &terraform.InputValue{Value: val, SourceRange: arg.Expr.Range()}
+			}
+		}
+		for _, diag := range terraform.CheckValueTypes(values, declVars) {
+			if !slices.ContainsFunc(diags, func(d *hcl.Diagnostic) bool {
+				return d.Detail == diag.Detail && *d.Subject == *diag.Subject
+			}) {
+				diags = diags.Append(diag)
+			}
+		}
+	}
+
+	for _, diag := range diags {
+		var expr hcl.Expression
+		for _, arg := range args {
+			if arg.NameRange == *diag.Subject || arg.Expr.Range() == *diag.Subject {
+				expr = arg.Expr
+			}
+		}
+
+		// HINT: WithExpressionContext never returns errors since the passed function doesn't return errors.
+		_ = r.WithExpressionContext(expr, func() error {
+			r.EmitIssue(moduleInputRule, diag.Detail, *diag.Subject, false)
+			return nil
+		})
+	}
+}
+
 // LookupIssues returns issues according to the received files
 func (r *Runner) LookupIssues(files ...string) Issues {
 	if len(files) == 0 {
@@ -224,6 +679,71 @@ func (r *Runner) Sources() map[string][]byte {
 	return r.TFConfig.Module.Sources
 }
 
+// EvaluateExpr evaluates the expression in the context of the runner.
+// Unlike Evaluator.EvaluateExpr, errors in filesystem functions, such as missing files
+// and files outside the sandbox, are reported as issues at the call site and the result
+// is treated as unknown, so that these errors do not abort rules evaluating the expression.
+func (r *Runner) EvaluateExpr(expr hcl.Expression, wantType cty.Type) (cty.Value, hcl.Diagnostics) {
+	val, diags := r.Ctx.EvaluateExpr(expr, wantType)
+	if !diags.HasErrors() {
+		return val, diags
+	}
+
+	r.issuesMu.Lock()
+	defer r.issuesMu.Unlock()
+
+	remain := hcl.Diagnostics{}
+	for _, diag := range diags {
+		if !isFileAccessError(diag) {
+			remain = append(remain, diag)
+			continue
+		}
+
+		location := diag.Subject
+		if diag.Context != nil {
+			location = diag.Context
+		}
+		// The same expression can be evaluated by multiple rules.
+		if r.hasIssue(fileAccessRule, diag.Detail, *location) {
+			continue
+		}
+		// HINT: WithExpressionContext never returns errors since the passed function doesn't return errors.
+		_ = r.WithExpressionContext(expr, func() error {
+			r.EmitIssue(fileAccessRule, diag.Detail, *location, false)
+			return nil
+		})
+	}
+	if remain.HasErrors() {
+		return val, remain
+	}
+	return cty.UnknownVal(wantType), remain
+}
+
+// isFileAccessError returns true if the diagnostic is caused by a filesystem function
+// that refers to a missing file or a file outside the sandbox.
+func isFileAccessError(diag *hcl.Diagnostic) bool {
+	extra, ok := hcl.DiagnosticExtra[hclsyntax.FunctionCallDiagExtra](diag)
+	if !ok {
+		return false
+	}
+	err := extra.FunctionCallError()
+	return errors.Is(err, funcs.ErrFileNotFound) || errors.Is(err, funcs.ErrOutsideSandbox)
+}
+
+// hasIssue returns true if the issue has already been emitted at the location.
+// In child modules, the location is the last caller of the issue.
+func (r *Runner) hasIssue(rule Rule, message string, location hcl.Range) bool {
+	for _, issue := range r.Issues {
+		if issue.Rule.Name() != rule.Name() || issue.Message != message {
+			continue
+		}
+		if issue.Range == location || (len(issue.Callers) > 0 && issue.Callers[len(issue.Callers)-1] == location) {
+			return true
+		}
+	}
+	return false
+}
+
 // EmitIssue builds an issue and accumulates it.
 // Returns true if the issue was not ignored by annotations.
 func (r *Runner) EmitIssue(rule Rule, message string, location hcl.Range, fixable bool) bool {
@@ -296,18 +816,92 @@ func (r *Runner) ClearChanges() {
 }
 
 func (r *Runner) emitIssue(issue *Issue) bool {
+	if !r.ruleEnabled(issue.Rule) {
+		return false
+	}
 	if annotations, ok := r.annotations[issue.Range.Filename]; ok {
 		for _, annotation := range annotations {
 			if annotation.IsAffected(issue) {
 				log.Printf("[INFO] %s (%s) is ignored by %s", issue.Range.String(), issue.Rule.Name(), annotation.String())
+				r.annotationUsage.record(annotation)
 				return false
 			}
 		}
 	}
+	for _, ignore := range r.config.Ignores {
+		if ignore.IsAffected(issue, r.modulePath(issue.Range.Filename), func() string { return r.blockAddress(issue.Range) }) {
+			log.Printf("[INFO] %s (%s) is suppressed by the ignore block in the config file", issue.Range.String(), issue.Rule.Name())
+			issue.Suppression = &Suppression{Reason: ignore.Reason}
+			r.Issues = append(r.Issues, issue)
+			return false
+		}
+	}
 	r.Issues = append(r.Issues, issue)
 	return true
 }
 
+// modulePath returns the path of the file relative to the module directory.
+// Paths outside the working directory, such as the module cache, are returned as is.
+func (r *Runner) modulePath(filename string) string {
+	if rel, err := filepath.Rel(r.baseDir, filename); err == nil {
+		return rel
+	}
+	return filename
+}
+
+// blockAddress returns the address of the top-level block containing the range,
+// such as "aws_instance.web" or "module.vpc". Only native syntax files are supported.
+// It returns an empty string if the range is not in any block.
+//
+// Issues emitted by module runners always point to the root module,
+// so the file is looked up from there.
+func (r *Runner) blockAddress(rng hcl.Range) string {
+	file, exists := r.TFConfig.Root.Module.Files[rng.Filename]
+	if !exists {
+		return ""
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return ""
+	}
+
+	for _, block := range body.Blocks {
+		if block.Range().Start.Byte > rng.Start.Byte || rng.End.Byte > block.Range().End.Byte {
+			continue
+		}
+
+		switch block.Type {
+		case "resource":
+			if len(block.Labels) == 2 {
+				return strings.Join(block.Labels, ".")
+			}
+		case "data", "ephemeral":
+			if len(block.Labels) == 2 {
+				return strings.Join(append([]string{block.Type}, block.Labels...), ".")
+			}
+		case "module", "output":
+			if len(block.Labels) == 1 {
+				return block.Type + "." + block.Labels[0]
+			}
+		case "variable":
+			if len(block.Labels) == 1 {
+				return "var." + block.Labels[0]
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// ruleEnabled returns whether issues of the rule should be reported.
+// Plugins only emit issues of enabled rules, so only built-in rules are checked here.
+func (r *Runner) ruleEnabled(rule Rule) bool {
+	if builtin, ok := rule.(*builtinRule); ok {
+		return r.config.builtinRuleEnabled(builtin.name)
+	}
+	return true
+}
+
 func (r *Runner) listModuleVars(expr hcl.Expression) []*moduleVariable {
 	ret := []*moduleVariable{}
 	for _, ref := range listVarRefs(expr) {
@@ -2,8 +2,13 @@ package tflint
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -12,6 +17,7 @@ import (
 	sdk "github.com/nholuongut/tflint-plugin-sdk/tflint"
 	"github.com/nholuongut/tflint/terraform"
 	"github.com/nholuongut/tflint/terraform/addrs"
+	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -288,6 +294,666 @@ func Test_NewModuleRunners_withInvalidExpression(t *testing.T) {
 	})
 }
 
+func TestNewModuleRunners_checkModuleCallArguments(t *testing.T) {
+	want := Issues{
+		{
+			Rule:    moduleInputRule,
+			Message: `An argument named "instanse_count" is not expected here. The module "instance" does not declare a variable of that name. Did you mean "instance_count"?`,
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 5, Column: 3},
+				End:      hcl.Pos{Line: 5, Column: 17},
+			},
+		},
+		{
+			Rule:    moduleInputRule,
+			Message: `The argument "ami" is required by the module "instance", but no definition was found. The variable is declared at module/main.tf:1,1-15.`,
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1},
+				End:      hcl.Pos{Line: 1, Column: 18},
+			},
+		},
+		{
+			Rule:    moduleInputRule,
+			Message: `The given value is not suitable for var.tags declared at module/main.tf:11,1-16: map of string required.`,
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 6, Column: 20},
+				End:      hcl.Pos{Line: 6, Column: 32},
+			},
+		},
+	}
+
+	opts := cmp.Options{
+		cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+		cmpopts.IgnoreFields(Issue{}, "Source"),
+		cmp.AllowUnexported(builtinRule{}),
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{
+			name:    "single instance",
+			fixture: "module_call_arguments",
+		},
+		{
+			name:    "multiple instances",
+			fixture: "module_call_arguments_count",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withinFixtureDir(t, test.fixture, func() {
+				runner := testRunnerWithOsFs(t, moduleConfig())
+
+				_, err := NewModuleRunners(runner)
+				if err != nil {
+					t.Fatalf("Unexpected error occurred: %s", err)
+				}
+
+				// Issues must be reported once per module call, not per instance
+				if diff := cmp.Diff(want, runner.Issues, opts); diff != "" {
+					t.Error(diff)
+				}
+			})
+		})
+	}
+}
+
+func TestNewRunner_validateVariables(t *testing.T) {
+	tfvarsRange := hcl.Range{Filename: "terraform.tfvars", Start: hcl.Pos{Line: 1, Column: 17}, End: hcl.Pos{Line: 1, Column: 28}}
+
+	tests := []struct {
+		name   string
+		config string
+		values terraform.InputValues
+		want   Issues
+	}{
+		{
+			name: "valid value",
+			config: `
+variable "instance_type" {
+  validation {
+    condition     = startswith(var.instance_type, "t2.")
+    error_message = "Only t2 instances are allowed."
+  }
+}`,
+			values: terraform.InputValues{
+				"instance_type": {Value: cty.StringVal("t2.micro"), SourceType: terraform.ValueFromAutoFile, SourceRange: tfvarsRange},
+			},
+			want: Issues{},
+		},
+		{
+			name: "invalid value",
+			config: `
+variable "instance_type" {
+  validation {
+    condition     = startswith(var.instance_type, "t2.")
+    error_message = "Only t2 instances are allowed."
+  }
+}`,
+			values: terraform.InputValues{
+				"instance_type": {Value: cty.StringVal("m5.large"), SourceType: terraform.ValueFromAutoFile, SourceRange: tfvarsRange},
+			},
+			want: Issues{
+				{
+					Rule:    variableValidationRule,
+					Message: "Invalid value for var.instance_type: Only t2 instances are allowed.",
+					Range:   tfvarsRange,
+				},
+			},
+		},
+		{
+			name: "invalid value without error message",
+			config: `
+variable "instance_type" {
+  validation {
+    condition = startswith(var.instance_type, "t2.")
+  }
+}`,
+			values: terraform.InputValues{
+				"instance_type": {Value: cty.StringVal("m5.large"), SourceType: terraform.ValueFromAutoFile, SourceRange: tfvarsRange},
+			},
+			want: Issues{
+				{
+					Rule:    variableValidationRule,
+					Message: "Invalid value for var.instance_type: The value does not satisfy the validation rule declared at main.tf:3,3-13.",
+					Range:   tfvarsRange,
+				},
+			},
+		},
+		{
+			name: "unknown value",
+			config: `
+variable "instance_type" {
+  validation {
+    condition     = startswith(var.instance_type, "t2.")
+    error_message = "Only t2 instances are allowed."
+  }
+}`,
+			values: terraform.InputValues{
+				"instance_type": {Value: cty.UnknownVal(cty.String), SourceType: terraform.ValueFromAutoFile, SourceRange: tfvarsRange},
+			},
+			want: Issues{},
+		},
+		{
+			name: "not supplied externally",
+			config: `
+variable "instance_type" {
+  validation {
+    condition     = startswith(var.instance_type, "t2.")
+    error_message = "Only t2 instances are allowed."
+  }
+}`,
+			values: terraform.InputValues{
+				"instance_type": {Value: cty.StringVal("m5.large")},
+			},
+			want: Issues{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := TestRunner(t, map[string]string{"main.tf": test.config}).TFConfig
+
+			runner, err := NewRunner("", EmptyConfig(), map[string]Annotations{}, config, test.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := cmp.Options{
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmp.AllowUnexported(builtinRule{}),
+			}
+			if diff := cmp.Diff(test.want, runner.Issues, opts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewRunner_checkInputValues(t *testing.T) {
+	config := TestRunner(t, map[string]string{"main.tf": `
+variable "instance_type" {
+  type = string
+}
+variable "instance_count" {
+  type = number
+}`}).TFConfig
+
+	rng := func(filename string, line int) hcl.Range {
+		return hcl.Range{Filename: filename, Start: hcl.Pos{Line: line, Column: 1}, End: hcl.Pos{Line: line, Column: 1}}
+	}
+
+	tests := []struct {
+		name   string
+		values []terraform.InputValues
+		want   Issues
+	}{
+		{
+			name: "valid values",
+			values: []terraform.InputValues{
+				{
+					"instance_type":  {Value: cty.StringVal("t2.micro"), SourceType: terraform.ValueFromAutoFile, SourceRange: rng("terraform.tfvars", 1)},
+					"instance_count": {Value: cty.StringVal("1"), SourceType: terraform.ValueFromAutoFile, SourceRange: rng("terraform.tfvars", 2)},
+				},
+			},
+			want: Issues{},
+		},
+		{
+			name: "undeclared in values files",
+			values: []terraform.InputValues{
+				{
+					"instance_typ": {Value: cty.StringVal("t2.micro"), SourceType: terraform.ValueFromAutoFile, SourceRange: rng("terraform.tfvars", 1)},
+				},
+				{
+					"ami": {Value: cty.StringVal("ami-1234"), SourceType: terraform.ValueFromNamedFile, SourceRange: rng("prod.tfvars", 1)},
+				},
+			},
+			want: Issues{
+				{
+					Rule:    undeclaredVariableValueRule,
+					Message: `A variable named "instance_typ" was assigned, but the root module does not declare a variable of that name. Did you mean "instance_type"?`,
+					Range:   rng("terraform.tfvars", 1),
+				},
+				{
+					Rule:    undeclaredVariableValueRule,
+					Message: `A variable named "ami" was assigned, but the root module does not declare a variable of that name.`,
+					Range:   rng("prod.tfvars", 1),
+				},
+			},
+		},
+		{
+			name: "undeclared in environment variables",
+			values: []terraform.InputValues{
+				{
+					"ami": {Value: cty.StringVal("ami-1234"), SourceType: terraform.ValueFromEnvVar, SourceRange: rng("<value for var.ami>", 1)},
+				},
+			},
+			want: Issues{},
+		},
+		{
+			name: "type mismatch in overridden values",
+			values: []terraform.InputValues{
+				{
+					"instance_count": {Value: cty.StringVal("one"), SourceType: terraform.ValueFromAutoFile, SourceRange: rng("terraform.tfvars", 1)},
+				},
+				{
+					"instance_count": {Value: cty.NumberIntVal(1), SourceType: terraform.ValueFromCLIArg, SourceRange: rng("<value for var.instance_count>", 1)},
+				},
+			},
+			want: Issues{
+				{
+					Rule:    variableValueTypeRule,
+					Message: "The given value is not suitable for var.instance_count declared at main.tf:5,1-26: a number is required.",
+					Range:   rng("terraform.tfvars", 1),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner, err := NewRunner("", EmptyConfig(), map[string]Annotations{}, config, test.values...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := cmp.Options{
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmp.AllowUnexported(builtinRule{}),
+			}
+			if diff := cmp.Diff(test.want, runner.Issues, opts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewRunner_workspace(t *testing.T) {
+	t.Setenv("TF_WORKSPACE", "staging")
+
+	withWorkspaces := func(workspaces ...string) *Config {
+		config := EmptyConfig()
+		config.Workspaces = workspaces
+		return config
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   string
+	}{
+		{
+			name:   "detected from the environment",
+			config: EmptyConfig(),
+			want:   "staging",
+		},
+		{
+			name:   "pinned",
+			config: withWorkspaces("prod"),
+			want:   "prod",
+		},
+		{
+			name:   "multiple workspaces",
+			config: withWorkspaces("dev", "prod"),
+			want:   "dev",
+		},
+		{
+			name:   "for workspace",
+			config: withWorkspaces("dev", "prod").ForWorkspace("prod"),
+			want:   "prod",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := TestRunnerWithConfig(t, map[string]string{"main.tf": ""}, test.config)
+			if runner.Ctx.Meta.Env != test.want {
+				t.Errorf("want %s, got %s", test.want, runner.Ctx.Meta.Env)
+			}
+		})
+	}
+}
+
+func TestCheckConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   Issues
+	}{
+		{
+			name: "resource preconditions and postconditions",
+			config: `
+variable "instance_type" {
+  default = "m5.large"
+}
+resource "aws_instance" "main" {
+  instance_type = var.instance_type
+
+  lifecycle {
+    precondition {
+      condition     = var.instance_type == "t2.micro"
+      error_message = "Only t2.micro is allowed."
+    }
+    postcondition {
+      condition     = var.instance_type != ""
+      error_message = "The instance type must not be empty."
+    }
+    postcondition {
+      condition     = self.ami != ""
+      error_message = "The AMI must not be empty."
+    }
+  }
+}`,
+			want: Issues{
+				{
+					Rule:    customConditionRule,
+					Message: "Resource precondition failed: Only t2.micro is allowed.",
+					Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 10, Column: 23}, End: hcl.Pos{Line: 10, Column: 54}},
+				},
+			},
+		},
+		{
+			name: "data source preconditions",
+			config: `
+data "aws_ami" "main" {
+  lifecycle {
+    precondition {
+      condition = 1 > 2
+    }
+  }
+}`,
+			want: Issues{
+				{
+					Rule:    customConditionRule,
+					Message: "Resource precondition failed: The condition evaluated to false.",
+					Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 5, Column: 19}, End: hcl.Pos{Line: 5, Column: 24}},
+				},
+			},
+		},
+		{
+			name: "output preconditions",
+			config: `
+variable "env" {
+  default = "dev"
+}
+output "env" {
+  value = var.env
+
+  precondition {
+    condition     = contains(["stg", "prod"], var.env)
+    error_message = "Invalid env: ${var.env}"
+  }
+}`,
+			want: Issues{
+				{
+					Rule:    customConditionRule,
+					Message: "Module output value precondition failed: Invalid env: dev",
+					Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 9, Column: 21}, End: hcl.Pos{Line: 9, Column: 55}},
+				},
+			},
+		},
+		{
+			name: "check assertions",
+			config: `
+variable "env" {
+  default = "dev"
+}
+check "env" {
+  assert {
+    condition     = var.env == "prod"
+    error_message = "Not a production environment."
+  }
+}`,
+			want: Issues{
+				{
+					Rule:    checkAssertionRule,
+					Message: "Check block assertion failed: Not a production environment.",
+					Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 7, Column: 21}, End: hcl.Pos{Line: 7, Column: 38}},
+				},
+			},
+		},
+		{
+			name: "unknown conditions",
+			config: `
+variable "env" {}
+resource "aws_instance" "main" {
+  count = 2
+
+  lifecycle {
+    precondition {
+      condition     = var.env == "prod"
+      error_message = "Not a production environment."
+    }
+    precondition {
+      condition     = count.index == 0
+      error_message = "Only the first instance is allowed."
+    }
+  }
+}`,
+			want: Issues{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := TestRunner(t, map[string]string{"main.tf": test.config})
+
+			runner.CheckConditions()
+
+			opts := cmp.Options{
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmpopts.IgnoreFields(Issue{}, "Source"),
+				cmp.AllowUnexported(builtinRule{}),
+			}
+			if diff := cmp.Diff(test.want, runner.Issues, opts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewRunner_checkTestRuns(t *testing.T) {
+	runner := TestRunner(t, map[string]string{
+		"main.tf": `
+variable "instance_type" {
+  default = "t2.micro"
+}
+
+variable "instance_count" {
+  type = number
+}`,
+		"main.tftest.hcl": `
+variables {
+  instance_count = "many"
+  shared         = "foo"
+}
+
+run "default" {}
+
+run "large" {
+  variables {
+    instance_type = "m5.large"
+  }
+}
+
+run "undeclared" {
+  variables {
+    instance_typo = "m5.large"
+  }
+}
+
+run "alternate" {
+  variables {
+    unknown = "foo"
+  }
+
+  module {
+    source = "./testing/setup"
+  }
+}`,
+	})
+
+	want := Issues{
+		{
+			Rule:    variableValueTypeRule,
+			Message: `The given value is not suitable for var.instance_count declared at main.tf:6,1-26: a number is required.`,
+			Range: hcl.Range{
+				Filename: "main.tftest.hcl",
+				Start:    hcl.Pos{Line: 3, Column: 20},
+				End:      hcl.Pos{Line: 3, Column: 26},
+			},
+		},
+		{
+			Rule:    undeclaredVariableValueRule,
+			Message: `A variable named "instance_typo" was assigned, but the root module does not declare a variable of that name. Did you mean "instance_type"?`,
+			Range: hcl.Range{
+				Filename: "main.tftest.hcl",
+				Start:    hcl.Pos{Line: 17, Column: 21},
+				End:      hcl.Pos{Line: 17, Column: 31},
+			},
+		},
+	}
+
+	opts := cmp.Options{
+		cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+		cmpopts.IgnoreFields(Issue{}, "Source"),
+		cmp.AllowUnexported(builtinRule{}),
+	}
+	if diff := cmp.Diff(want, runner.Issues, opts); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRunner_EvaluateExpr(t *testing.T) {
+	dir := t.TempDir()
+	sandbox := filepath.Join(dir, "sandbox")
+	if err := os.Mkdir(sandbox, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sandbox, "hello.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		want      cty.Value
+		wantErr   bool
+		wantIssue string
+	}{
+		{
+			name: "file in the sandbox",
+			expr: fmt.Sprintf(`file(%q)`, filepath.Join(sandbox, "hello.txt")),
+			want: cty.StringVal("hello"),
+		},
+		{
+			name:      "file outside the sandbox",
+			expr:      fmt.Sprintf(`file(%q)`, filepath.Join(sandbox, "..", "secret.txt")),
+			want:      cty.UnknownVal(cty.String),
+			wantIssue: `Call to function "file" failed: cannot access`,
+		},
+		{
+			name:      "missing file",
+			expr:      fmt.Sprintf(`"${file(%q)}!"`, filepath.Join(sandbox, "missing.txt")),
+			want:      cty.UnknownVal(cty.String),
+			wantIssue: `Call to function "file" failed: no file exists at`,
+		},
+		{
+			name:      "fileset outside the sandbox",
+			expr:      fmt.Sprintf(`fileset(%q, "../*.txt")`, sandbox),
+			want:      cty.UnknownVal(cty.String),
+			wantIssue: `Call to function "fileset" failed: cannot access`,
+		},
+		{
+			name:    "other errors",
+			expr:    `file(1, 2)`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := EmptyConfig()
+			config.SandboxDir = sandbox
+			runner := TestRunnerWithConfig(t, map[string]string{"main.tf": ""}, config)
+
+			expr, diags := hclsyntax.ParseExpression([]byte(test.expr), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			// Evaluate twice to ensure that the issue is not duplicated
+			for range 2 {
+				got, diags := runner.EvaluateExpr(expr, cty.String)
+				if diags.HasErrors() != test.wantErr {
+					t.Fatalf("want error: %t, got: %s", test.wantErr, diags)
+				}
+				if test.wantErr {
+					return
+				}
+				if !got.RawEquals(test.want) {
+					t.Errorf("want %#v, got %#v", test.want, got)
+				}
+			}
+
+			if test.wantIssue == "" {
+				if len(runner.Issues) > 0 {
+					t.Errorf("unexpected issues: %#v", runner.Issues)
+				}
+				return
+			}
+			if len(runner.Issues) != 1 {
+				t.Fatalf("want 1 issue, got %d", len(runner.Issues))
+			}
+			issue := runner.Issues[0]
+			if issue.Rule.Name() != "file_access" || !strings.HasPrefix(issue.Message, test.wantIssue) {
+				t.Errorf("unexpected issue: %s: %s", issue.Rule.Name(), issue.Message)
+			}
+			if issue.Range.Filename != "main.tf" {
+				t.Errorf("unexpected range: %s", issue.Range)
+			}
+		})
+	}
+}
+
+func TestRunner_EvaluateExpr_concurrent(t *testing.T) {
+	config := EmptyConfig()
+	config.SandboxDir = t.TempDir()
+	runner := TestRunnerWithConfig(t, map[string]string{"main.tf": ""}, config)
+
+	src := fmt.Sprintf(`file(%q)`, filepath.Join(config.SandboxDir, "missing.txt"))
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	// The root runner is evaluated from the plugin servers of module runners in parallel
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, diags := runner.EvaluateExpr(expr, cty.String); diags.HasErrors() {
+				t.Error(diags)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(runner.Issues) != 1 {
+		t.Fatalf("want 1 issue, got %d", len(runner.Issues))
+	}
+}
+
 func Test_RunnerFiles(t *testing.T) {
 	runner := TestRunner(t, map[string]string{
 		"main.tf": "",
@@ -712,31 +1378,289 @@ func Test_EmitIssue(t *testing.T) {
 					Source:  []byte("bar = 2"),
 				},
 			},
-			Applied: true,
+			Applied: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			runner := testRunnerWithAnnotations(t, sources, tc.Annotations)
+			if tc.Module != nil {
+				runner.TFConfig.Path = []string{"module", "module1"}
+				runner.currentExpr = tc.Module.currentExpr
+				runner.modVars = tc.Module.variables
+			}
+
+			got := runner.EmitIssue(tc.Rule, tc.Message, tc.Location, tc.Fixable)
+			if got != tc.Applied {
+				t.Fatalf("expected %v, got %v", tc.Applied, got)
+			}
+
+			if diff := cmp.Diff(runner.Issues.Sort(), tc.Expected); diff != "" {
+				t.Fatalf("diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunner_EmitIssue_builtinRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config)
+		want   []string
+	}{
+		{
+			name:   "default",
+			config: func(c *Config) {},
+			want:   []string{"module_input", "test_rule"},
+		},
+		{
+			name: "disabled by rule block",
+			config: func(c *Config) {
+				c.Rules["module_input"] = &RuleConfig{Name: "module_input", Enabled: false}
+			},
+			want: []string{"test_rule"},
+		},
+		{
+			name: "disabled by default",
+			config: func(c *Config) {
+				c.DisabledByDefault = true
+			},
+			want: []string{"test_rule"},
+		},
+		{
+			name: "enabled by rule block with disabled by default",
+			config: func(c *Config) {
+				c.DisabledByDefault = true
+				c.Rules["module_input"] = &RuleConfig{Name: "module_input", Enabled: true}
+			},
+			want: []string{"module_input", "test_rule"},
+		},
+		{
+			name: "only",
+			config: func(c *Config) {
+				c.Only = []string{"custom_condition"}
+				c.Rules["custom_condition"] = &RuleConfig{Name: "custom_condition", Enabled: true}
+			},
+			want: []string{"test_rule"},
 		},
 	}
 
-	for _, tc := range cases {
-		t.Run(tc.Name, func(t *testing.T) {
-			runner := testRunnerWithAnnotations(t, sources, tc.Annotations)
-			if tc.Module != nil {
-				runner.TFConfig.Path = []string{"module", "module1"}
-				runner.currentExpr = tc.Module.currentExpr
-				runner.modVars = tc.Module.variables
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := EmptyConfig()
+			test.config(config)
+			runner := TestRunnerWithConfig(t, map[string]string{"main.tf": ""}, config)
+
+			runner.EmitIssue(moduleInputRule, "module input", hcl.Range{Filename: "main.tf"}, false)
+			// Rules provided by plugins are always reported since plugins only emit issues of enabled rules
+			runner.EmitIssue(&testRule{}, "plugin rule", hcl.Range{Filename: "main.tf"}, false)
+
+			got := []string{}
+			for _, issue := range runner.Issues {
+				got = append(got, issue.Rule.Name())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
 			}
+		})
+	}
+}
 
-			got := runner.EmitIssue(tc.Rule, tc.Message, tc.Location, tc.Fixable)
-			if got != tc.Applied {
-				t.Fatalf("expected %v, got %v", tc.Applied, got)
+func TestRunner_EmitIssue_ignores(t *testing.T) {
+	sources := map[string]string{
+		"main.tf": `
+resource "aws_instance" "legacy_web" {
+  instance_type = "t1.micro"
+}
+
+resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+}
+
+data "aws_ami" "legacy" {
+  most_recent = true
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+
+locals {
+  name = "main"
+}`,
+		"generated_main.tf": `
+resource "aws_instance" "generated" {
+  instance_type = "t3.micro"
+}`,
+	}
+
+	location := func(filename string, substr string) hcl.Range {
+		start := strings.Index(sources[filename], substr)
+		if start < 0 {
+			t.Fatalf("%q is not found in %s", substr, filename)
+		}
+		return hcl.Range{
+			Filename: filename,
+			Start:    hcl.Pos{Byte: start},
+			End:      hcl.Pos{Byte: start + len(substr)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ignores  []*IgnoreConfig
+		location hcl.Range
+		want     *Suppression
+	}{
+		{
+			name:     "no ignores",
+			location: location("main.tf", `"t1.micro"`),
+			want:     nil,
+		},
+		{
+			name:     "rule only",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Reason: "Not applicable"}},
+			location: location("main.tf", `"t1.micro"`),
+			want:     &Suppression{Reason: "Not applicable"},
+		},
+		{
+			name:     "other rule",
+			ignores:  []*IgnoreConfig{{Rule: "other_rule"}},
+			location: location("main.tf", `"t1.micro"`),
+			want:     nil,
+		},
+		{
+			name:     "all rules with matched paths",
+			ignores:  []*IgnoreConfig{{Rule: "all", Paths: []string{"generated_*.tf"}, Reason: "Generated code"}},
+			location: location("generated_main.tf", `"t3.micro"`),
+			want:     &Suppression{Reason: "Generated code"},
+		},
+		{
+			name:     "unmatched paths",
+			ignores:  []*IgnoreConfig{{Rule: "all", Paths: []string{"generated_*.tf"}}},
+			location: location("main.tf", `"t1.micro"`),
+			want:     nil,
+		},
+		{
+			name:     "matched resource address",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Addresses: []string{"aws_instance.legacy_*"}}},
+			location: location("main.tf", `"t1.micro"`),
+			want:     &Suppression{},
+		},
+		{
+			name:     "unmatched resource address",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Addresses: []string{"aws_instance.legacy_*"}}},
+			location: location("main.tf", `"t2.micro"`),
+			want:     nil,
+		},
+		{
+			name:     "data source address",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Addresses: []string{"data.aws_ami.*"}}},
+			location: location("main.tf", `most_recent = true`),
+			want:     &Suppression{},
+		},
+		{
+			name:     "module address",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Addresses: []string{"module.vpc"}}},
+			location: location("main.tf", `"terraform-aws-modules/vpc/aws"`),
+			want:     &Suppression{},
+		},
+		{
+			name:     "block without address",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Addresses: []string{"*"}}},
+			location: location("main.tf", `"main"`),
+			want:     nil,
+		},
+		{
+			name:     "paths and addresses",
+			ignores:  []*IgnoreConfig{{Rule: "test_rule", Paths: []string{"main.tf"}, Addresses: []string{"aws_instance.generated"}}},
+			location: location("generated_main.tf", `"t3.micro"`),
+			want:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := EmptyConfig()
+			config.Ignores = test.ignores
+			runner := TestRunnerWithConfig(t, sources, config)
+
+			applied := runner.EmitIssue(&testRule{}, "This is test message", test.location, false)
+			if applied != (test.want == nil) {
+				t.Errorf("applied: want=%t, got=%t", test.want == nil, applied)
 			}
 
-			if diff := cmp.Diff(runner.Issues.Sort(), tc.Expected); diff != "" {
-				t.Fatalf("diff: %s", diff)
+			if len(runner.Issues) != 1 {
+				t.Fatalf("expected 1 issue, got %d", len(runner.Issues))
+			}
+			if diff := cmp.Diff(test.want, runner.Issues[0].Suppression); diff != "" {
+				t.Error(diff)
 			}
 		})
 	}
 }
 
+func TestRunner_EmitIssue_ignoresWithBaseDir(t *testing.T) {
+	// Simulate --chdir=module, where filenames are prefixed with the directory
+	originalWd := t.TempDir()
+	dir := filepath.Join(originalWd, "module")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "generated_main.tf"), []byte(`
+resource "aws_instance" "generated" {
+  instance_type = "t3.micro"
+}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	config := EmptyConfig()
+	loader, err := terraform.NewLoader(afero.Afero{Fs: afero.NewOsFs()}, originalWd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, diags := loader.LoadConfig(".", config.CallModuleType)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	config.Ignores = []*IgnoreConfig{{Rule: "all", Paths: []string{"generated_*.tf"}, Reason: "Generated code"}}
+	runner, err := NewRunner(originalWd, config, map[string]Annotations{}, cfg, terraform.InputValues{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location := hcl.Range{
+		Filename: filepath.Join("module", "generated_main.tf"),
+		Start:    hcl.Pos{Line: 3, Column: 19},
+		End:      hcl.Pos{Line: 3, Column: 29},
+	}
+	if runner.EmitIssue(&testRule{}, "This is test message", location, false) {
+		t.Error("the issue is not suppressed")
+	}
+
+	if len(runner.Issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(runner.Issues))
+	}
+	if diff := cmp.Diff(&Suppression{Reason: "Generated code"}, runner.Issues[0].Suppression); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestApplyChanges(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -841,3 +1765,355 @@ func Test_listVarRefs(t *testing.T) {
 		}
 	}
 }
+
+func TestRunner_EvaluateExpr_deterministic(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		results map[string]string
+		expr    string
+		want    cty.Value
+	}{
+		{
+			name: "disabled",
+			expr: `plantimestamp()`,
+			want: cty.UnknownVal(cty.String),
+		},
+		{
+			name:    "default results",
+			enabled: true,
+			expr:    `"${formatdate("YYYY", plantimestamp())}-${uuid()}"`,
+			want:    cty.StringVal("1970-00000000-0000-0000-0000-000000000000"),
+		},
+		{
+			name:    "configured results",
+			enabled: true,
+			results: map[string]string{"timestamp": "2024-01-01T00:00:00Z"},
+			expr:    `"${formatdate("YYYY", timestamp())}-${uuid()}"`,
+			want:    cty.StringVal("2024-00000000-0000-0000-0000-000000000000"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := EmptyConfig()
+			config.Deterministic = test.enabled
+			config.DeterministicResults = test.results
+			runner := TestRunnerWithConfig(t, map[string]string{"main.tf": ""}, config)
+
+			expr, diags := hclsyntax.ParseExpression([]byte(test.expr), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got, diags := runner.EvaluateExpr(expr, cty.String)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("want %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestNewRunner_checkFunctionAvailability(t *testing.T) {
+	src := `
+locals {
+  prefixed = startswith("t2.micro", "t2.")
+  upper    = upper("t2.micro")
+  trimmed  = provider::terraform::trim("t2.micro")
+}`
+
+	tests := []struct {
+		name    string
+		version string
+		want    Issues
+	}{
+		{
+			name: "not configured",
+			want: Issues{},
+		},
+		{
+			name:    "all functions available",
+			version: "1.8.0",
+			want:    Issues{},
+		},
+		{
+			name:    "unavailable functions",
+			version: "1.2.0",
+			want: Issues{
+				{
+					Rule:    unavailableFunctionRule,
+					Message: `Function "startswith" is not available in Terraform v1.2.0. It was introduced in v1.3.0.`,
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 14},
+						End:      hcl.Pos{Line: 3, Column: 24},
+					},
+				},
+				{
+					Rule:    unavailableFunctionRule,
+					Message: `Function "provider::terraform::trim" is not available in Terraform v1.2.0. It was introduced in v1.8.0.`,
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 14},
+						End:      hcl.Pos{Line: 5, Column: 39},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := TestRunner(t, map[string]string{"main.tf": src}).TFConfig
+
+			cfg := EmptyConfig()
+			cfg.TerraformVersion = test.version
+			runner, err := NewRunner("", cfg, map[string]Annotations{}, config, terraform.InputValues{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := cmp.Options{
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmpopts.IgnoreFields(Issue{}, "Source"),
+				cmp.AllowUnexported(builtinRule{}),
+			}
+			if diff := cmp.Diff(test.want, runner.Issues, opts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewModuleRunners_checkFunctionAvailability(t *testing.T) {
+	withinFixtureDir(t, "module_function_availability", func() {
+		config := moduleConfig()
+		config.TerraformVersion = "1.2.0"
+		runner := testRunnerWithOsFs(t, config)
+
+		runners, err := NewModuleRunners(runner)
+		if err != nil {
+			t.Fatalf("Unexpected error occurred: %s", err)
+		}
+
+		issues := Issues{}
+		for _, r := range append(runners, runner) {
+			issues = append(issues, r.Issues...)
+		}
+
+		// Calls in the local module are reported once for all module calls and instances,
+		// while calls in the installed module are not reported.
+		want := Issues{
+			{
+				Rule:    unavailableFunctionRule,
+				Message: `Function "startswith" is not available in Terraform v1.2.0. It was introduced in v1.3.0.`,
+				Range: hcl.Range{
+					Filename: filepath.Join("module", "main.tf"),
+					Start:    hcl.Pos{Line: 2, Column: 14},
+					End:      hcl.Pos{Line: 2, Column: 24},
+				},
+			},
+		}
+		opts := cmp.Options{
+			cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+			cmpopts.IgnoreFields(Issue{}, "Source"),
+			cmp.AllowUnexported(builtinRule{}),
+		}
+		if diff := cmp.Diff(want, issues, opts); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestNewRunner_checkAnnotations(t *testing.T) {
+	src := `
+resource "aws_instance" "foo" {
+  # tflint-ignore: all
+  instance_type = "t2.micro"
+
+  # tflint-ignore: all -- migrated from the old module
+  ami = "ami-12345678"
+
+  # tflint-ignore: all -- temporary exception [until 2000-01-01]
+  key_name = "foo"
+}`
+
+	tests := []struct {
+		name          string
+		requireReason bool
+		want          Issues
+	}{
+		{
+			name: "reason not required",
+			want: Issues{
+				{
+					Rule:    expiredAnnotationRule,
+					Message: "Annotation expired after 2000-01-01 and no longer ignores issues",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 3},
+						End:      hcl.Pos{Line: 10, Column: 1},
+					},
+				},
+			},
+		},
+		{
+			name:          "reason required",
+			requireReason: true,
+			want: Issues{
+				{
+					Rule:    annotationReasonRule,
+					Message: `Annotation must have a reason. Write it after "--", e.g. "tflint-ignore: rule_name -- reason"`,
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3},
+						End:      hcl.Pos{Line: 4, Column: 1},
+					},
+				},
+				{
+					Rule:    expiredAnnotationRule,
+					Message: "Annotation expired after 2000-01-01 and no longer ignores issues",
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 9, Column: 3},
+						End:      hcl.Pos{Line: 10, Column: 1},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := TestRunner(t, map[string]string{"main.tf": src}).TFConfig
+
+			file, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			annotations, diags := NewAnnotations("main.tf", file)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			cfg := EmptyConfig()
+			cfg.RequireAnnotationReason = test.requireReason
+			runner, err := NewRunner("", cfg, map[string]Annotations{"main.tf": annotations}, config, terraform.InputValues{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := cmp.Options{
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmpopts.IgnoreFields(Issue{}, "Source"),
+				cmp.AllowUnexported(builtinRule{}),
+			}
+			if diff := cmp.Diff(test.want, runner.Issues, opts); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRunner_UnusedAnnotationIssues(t *testing.T) {
+	withinFixtureDir(t, "nested_module_vars", func() {
+		annotation := func(content string, line int) *LineAnnotation {
+			return &LineAnnotation{
+				Content: content,
+				Token: hclsyntax.Token{
+					Type:  hclsyntax.TokenComment,
+					Range: hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: line}},
+				},
+			}
+		}
+		annotations := map[string]Annotations{
+			"main.tf": {
+				annotation("test_rule", 1),
+				annotation("test_rule", 3),
+				annotation("test_rule, unknown_rule", 5),
+				annotation("all", 6),
+				annotation("variable_validation", 6),
+				&LineAnnotation{
+					Content: "test_rule",
+					Expiry:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+					Token: hclsyntax.Token{
+						Type:  hclsyntax.TokenComment,
+						Range: hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 2}},
+					},
+				},
+			},
+		}
+
+		config := moduleConfig()
+		originalWd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loader, err := terraform.NewLoader(afero.Afero{Fs: afero.NewOsFs()}, originalWd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, diags := loader.LoadConfig(".", config.CallModuleType)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		runner, err := NewRunner(originalWd, config, annotations, cfg, terraform.InputValues{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		runners, err := NewModuleRunners(runner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Discard issues reported while setting up runners, such as expired annotations
+		runner.Issues = Issues{}
+
+		// Ignored by the annotation at line 1 from the root module runner
+		runner.EmitIssue(&testRule{}, "root", hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1}}, false)
+		// Ignored by the annotation at line 3 from the module runner, since var.foo is declared at line 4
+		expr, diags := hclsyntax.ParseExpression([]byte("var.foo"), "module/main.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		err = runners[0].WithExpressionContext(expr, func() error {
+			runners[0].EmitIssue(&testRule{}, "module", expr.Range(), false)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runner.Issues) > 0 || len(runners[0].Issues) > 0 {
+			t.Fatalf("issues must be ignored, but got root=%#v, module=%#v", runner.Issues, runners[0].Issues)
+		}
+
+		got := runner.UnusedAnnotationIssues([]string{"test_rule"})
+
+		want := Issues{
+			{
+				Rule:    unusedAnnotationRule,
+				Message: `Annotation refers to unknown rules: "unknown_rule"`,
+				Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 5}},
+			},
+			{
+				Rule:    unusedAnnotationRule,
+				Message: "Annotation did not ignore any issues",
+				Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 6}},
+			},
+			{
+				Rule:    unusedAnnotationRule,
+				Message: "Annotation did not ignore any issues",
+				Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 6}},
+			},
+		}
+		opts := cmp.Options{
+			cmpopts.IgnoreFields(Issue{}, "Source"),
+			cmp.AllowUnexported(builtinRule{}),
+		}
+		if diff := cmp.Diff(want, got, opts); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
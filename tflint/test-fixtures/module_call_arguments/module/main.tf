@@ -0,0 +1,14 @@
+variable "ami" {
+  type = string
+}
+
+variable "instance_type" {}
+
+variable "instance_count" {
+  default = 1
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
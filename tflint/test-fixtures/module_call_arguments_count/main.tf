@@ -0,0 +1,9 @@
+module "instance" {
+  source = "./module"
+
+  instance_type  = "t2.micro"
+  instanse_count = 2
+  tags           = "production"
+
+  count = 3
+}
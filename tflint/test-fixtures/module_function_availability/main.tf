@@ -0,0 +1,13 @@
+module "instances" {
+  source = "./module"
+  count  = 2
+}
+
+module "instance" {
+  source = "./module"
+}
+
+module "remote" {
+  source  = "hashicorp/consul/aws"
+  version = "0.9.0"
+}
@@ -0,0 +1,4 @@
+locals {
+  prefixed = startswith("t2.micro", "t2.")
+  upper    = upper("t2.micro")
+}
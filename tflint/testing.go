@@ -34,6 +34,7 @@ func TestRunnerWithConfig(t *testing.T, files map[string]string, config *Config)
 	if err != nil {
 		t.Fatal(err)
 	}
+	loader.SetLanguage(config.Language)
 
 	dirMap := map[string]*struct{}{}
 	for file := range files {